@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,6 +17,10 @@ import (
 	v1 "social/api/internal/controller/http/v1"
 	"social/api/internal/repo/postgres"
 	"social/api/internal/usecase"
+	"social/api/pkg/jwt"
+	"social/api/pkg/mailer"
+	"social/api/pkg/oidc"
+	"social/api/pkg/secretbox"
 )
 
 func main() {
@@ -34,16 +39,93 @@ func main() {
 	postRepo := postgres.NewPostRepo(pool)
 	commentRepo := postgres.NewCommentRepo(pool)
 	likeRepo := postgres.NewLikeRepo(pool)
+	reactionRepo := postgres.NewReactionRepo(pool)
 	followRepo := postgres.NewFollowRepo(pool)
+	sessionRepo := postgres.NewSessionRepo(pool)
+	tokenRepo := postgres.NewTokenRepo(pool)
+	mfaRepo := postgres.NewMFARepo(pool)
+	loginAttemptRepo := postgres.NewLoginAttemptRepo(pool)
+	auditRepo := postgres.NewAuditRepo(pool)
+	oneTimeTokenRepo := postgres.NewOneTimeTokenRepo(pool)
+	identityRepo := postgres.NewIdentityRepo(pool)
+	timelineRepo := postgres.NewTimelineRepo(pool)
+	feedStatsRepo := postgres.NewFeedStatsRepo(pool)
+	trendingRepo := postgres.NewTrendingRepo(pool)
+
+	// Initialize token manager
+	tokenManager := jwt.New(cfg.JWT.Secret,
+		jwt.TTL(time.Duration(cfg.JWT.TokenTTL)*time.Hour),
+		jwt.Issuer(cfg.JWT.Issuer),
+	)
+
+	// Initialize encryption for secrets at rest
+	secrets, err := secretbox.New(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatal("Invalid MFA encryption key:", err)
+	}
+
+	// Initialize mail delivery
+	var mail mailer.Mailer
+	switch cfg.Mailer.Driver {
+	case "smtp":
+		mail = mailer.NewSMTP(cfg.Mailer.SMTPHost, cfg.Mailer.SMTPPort, cfg.Mailer.SMTPUsername, cfg.Mailer.SMTPPassword, cfg.Mailer.From)
+	case "file":
+		mail, err = mailer.NewFile(cfg.Mailer.Dir)
+		if err != nil {
+			log.Fatal("Unable to create mail directory:", err)
+		}
+	case "memory":
+		mail = mailer.NewMemory()
+	default:
+		log.Fatalf("Unknown mailer driver %q", cfg.Mailer.Driver)
+	}
+
+	// Initialize OpenID Connect providers
+	oidcProviders := make(map[string]*oidc.Provider, len(cfg.OIDC.Providers))
+	for name, provider := range cfg.OIDC.Providers {
+		redirectURL := strings.TrimSuffix(cfg.OIDC.RedirectURL, "/") + "/" + name + "/callback"
+		oidcProviders[name] = oidc.New(provider.Issuer, provider.ClientID, provider.ClientSecret, redirectURL)
+	}
+
+	unverifiedAccess, err := usecase.ParseUnverifiedAccess(cfg.Verification.UnverifiedAccess)
+	if err != nil {
+		log.Fatal("Invalid verification config:", err)
+	}
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(userRepo)
-	postUseCase := usecase.NewPostUseCase(postRepo, userRepo)
-	commentUseCase := usecase.NewCommentUseCase(commentRepo, userRepo, postRepo)
-	interactionUseCase := usecase.NewInteractionUseCase(likeRepo, followRepo, userRepo)
+	sessionUseCase := usecase.NewSessionUseCase(sessionRepo, userRepo, tokenManager, time.Duration(cfg.JWT.RefreshTokenTTL)*time.Hour)
+	loginGuard := usecase.NewLoginGuard(loginAttemptRepo, auditRepo, usecase.LockoutPolicy{
+		MaxAttempts:      cfg.Lockout.MaxAttempts,
+		MaxAttemptsPerIP: cfg.Lockout.MaxAttemptsPerIP,
+		Window:           time.Duration(cfg.Lockout.Window) * time.Second,
+		BaseLockout:      time.Duration(cfg.Lockout.BaseLockout) * time.Second,
+		MaxLockout:       time.Duration(cfg.Lockout.MaxLockout) * time.Second,
+	})
+	verificationUseCase := usecase.NewVerificationUseCase(userRepo, oneTimeTokenRepo, mail, cfg.Verification.URL, time.Duration(cfg.Verification.TTL)*time.Hour)
+	userUseCase := usecase.NewUserUseCase(userRepo, mfaRepo, sessionUseCase, verificationUseCase, loginGuard, tokenManager, secrets, cfg.MFA.Issuer, time.Duration(cfg.Account.UsernameChangeInterval)*time.Hour)
+	passwordUseCase := usecase.NewPasswordUseCase(userRepo, oneTimeTokenRepo, sessionRepo, mail, cfg.Password.ResetURL, time.Duration(cfg.Password.ResetTTL)*time.Minute)
+	oidcUseCase := usecase.NewOIDCUseCase(oidcProviders, userRepo, identityRepo, mfaRepo, sessionUseCase, verificationUseCase, tokenManager, secrets)
+	tokenUseCase := usecase.NewTokenUseCase(tokenRepo, userRepo)
+	timelines := usecase.NewTimelines(timelineRepo, usecase.TimelinePolicy{
+		Workers:            cfg.Timeline.Workers,
+		QueueSize:          cfg.Timeline.QueueSize,
+		MaxFanOutFollowers: cfg.Timeline.MaxFanOutFollowers,
+		BackfillPosts:      cfg.Timeline.BackfillPosts,
+		RebuildPosts:       cfg.Timeline.RebuildPosts,
+	})
+	feedRanker := usecase.NewFeedRanker(feedStatsRepo, usecase.DefaultRankingWeights)
+	trending := usecase.NewTrendingRefresher(trendingRepo, usecase.TrendingPolicy{
+		Interval: time.Duration(cfg.Trending.Interval) * time.Second,
+		Window:   time.Duration(cfg.Trending.Window) * time.Hour,
+		Size:     cfg.Trending.Size,
+	})
+	postUseCase := usecase.NewPostUseCase(postRepo, userRepo, reactionRepo, trendingRepo, timelines, feedRanker, unverifiedAccess)
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, userRepo, postRepo, time.Duration(cfg.Comments.EditWindow)*time.Minute, unverifiedAccess)
+	interactionUseCase := usecase.NewInteractionUseCase(likeRepo, reactionRepo, followRepo, userRepo, postRepo, timelines, unverifiedAccess)
+	reactionUseCase := usecase.NewReactionUseCase(reactionRepo, postRepo, commentRepo, userRepo, cfg.Reactions.Emoji, unverifiedAccess)
 
 	// Initialize handler
-	handler := v1.NewHandler(userUseCase, postUseCase, commentUseCase, interactionUseCase)
+	handler := v1.NewHandler(userUseCase, sessionUseCase, passwordUseCase, verificationUseCase, oidcUseCase, tokenUseCase, postUseCase, commentUseCase, interactionUseCase, reactionUseCase, tokenManager)
 
 	// Initialize router
 	r := chi.NewRouter()
@@ -97,6 +179,12 @@ func main() {
 		serverStopCtx()
 	}()
 
+	// Fan out timeline updates in the background
+	timelines.Start()
+
+	// Keep the trending posts up to date
+	trending.Start()
+
 	// Run the server
 	log.Printf("server started on %s", cfg.HTTPServer.Address)
 	err = server.ListenAndServe()
@@ -107,5 +195,9 @@ func main() {
 	// Wait for server context to be stopped
 	<-serverCtx.Done()
 
+	// Apply the timeline updates that are still queued
+	timelines.Stop()
+	trending.Stop()
+
 	log.Println("server exited properly")
-}
\ No newline at end of file
+}
@@ -1,14 +1,29 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	Env        string `yaml:"env" env-default:"local"`
-	HTTPServer `yaml:"http_server"`
-	PG         `yaml:"postgres"`
-	JWT        `yaml:"jwt"`
+	Env          string `yaml:"env" env-default:"local"`
+	HTTPServer   `yaml:"http_server"`
+	PG           `yaml:"postgres"`
+	JWT          `yaml:"jwt"`
+	MFA          `yaml:"mfa"`
+	Lockout      `yaml:"lockout"`
+	Mailer       `yaml:"mailer"`
+	Password     `yaml:"password"`
+	Verification `yaml:"verification"`
+	Account      `yaml:"account"`
+	OIDC         `yaml:"oidc"`
+	Timeline     `yaml:"timeline"`
+	Trending     `yaml:"trending"`
+	Reactions    `yaml:"reactions"`
+	Comments     `yaml:"comments"`
 }
 
 type HTTPServer struct {
@@ -22,8 +37,93 @@ type PG struct {
 }
 
 type JWT struct {
-	Secret   string `env:"JWT_SECRET" env-required:"true"`
-	TokenTTL int    `yaml:"token_ttl" env-default:"1"`
+	Secret          string `env:"JWT_SECRET" env-required:"true"`
+	TokenTTL        int    `yaml:"token_ttl" env-default:"1"`
+	RefreshTokenTTL int    `yaml:"refresh_token_ttl" env:"JWT_REFRESH_TOKEN_TTL" env-default:"720"`
+	Issuer          string `yaml:"issuer" env:"JWT_ISSUER" env-default:"social-api"`
+}
+
+type MFA struct {
+	EncryptionKey string `env:"MFA_ENCRYPTION_KEY" env-required:"true"`
+	Issuer        string `yaml:"issuer" env:"MFA_ISSUER" env-default:"Social API"`
+}
+
+type Lockout struct {
+	MaxAttempts      int `yaml:"max_attempts" env:"LOGIN_MAX_ATTEMPTS" env-default:"5"`
+	MaxAttemptsPerIP int `yaml:"max_attempts_per_ip" env:"LOGIN_MAX_ATTEMPTS_PER_IP" env-default:"20"`
+	Window           int `yaml:"window" env:"LOGIN_ATTEMPT_WINDOW" env-default:"900"`
+	BaseLockout      int `yaml:"base_lockout" env:"LOGIN_BASE_LOCKOUT" env-default:"30"`
+	MaxLockout       int `yaml:"max_lockout" env:"LOGIN_MAX_LOCKOUT" env-default:"3600"`
+}
+
+type Mailer struct {
+	// Driver is one of smtp, file or memory
+	Driver       string `yaml:"driver" env:"MAILER_DRIVER" env-default:"file"`
+	From         string `yaml:"from" env:"MAILER_FROM" env-default:"Social API <no-reply@localhost>"`
+	Dir          string `yaml:"dir" env:"MAILER_DIR" env-default:"tmp/mail"`
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST" env-default:"localhost"`
+	SMTPPort     string `yaml:"smtp_port" env:"SMTP_PORT" env-default:"587"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+}
+
+type Password struct {
+	ResetURL string `yaml:"reset_url" env:"PASSWORD_RESET_URL" env-default:"http://localhost:8080/password/reset"`
+	ResetTTL int    `yaml:"reset_ttl" env:"PASSWORD_RESET_TTL" env-default:"60"`
+}
+
+type Verification struct {
+	URL string `yaml:"url" env:"EMAIL_VERIFICATION_URL" env-default:"http://localhost:8080/verify-email"`
+	TTL int    `yaml:"ttl" env:"EMAIL_VERIFICATION_TTL" env-default:"48"`
+	// UnverifiedAccess is one of full, no_posts or read_only
+	UnverifiedAccess string `yaml:"unverified_access" env:"UNVERIFIED_ACCESS" env-default:"no_posts"`
+}
+
+type Account struct {
+	// UsernameChangeInterval is the minimum number of hours between username changes
+	UsernameChangeInterval int `yaml:"username_change_interval" env:"USERNAME_CHANGE_INTERVAL" env-default:"720"`
+}
+
+type Timeline struct {
+	Workers   int `yaml:"workers" env:"TIMELINE_WORKERS" env-default:"4"`
+	QueueSize int `yaml:"queue_size" env:"TIMELINE_QUEUE_SIZE" env-default:"1024"`
+	// Authors with at least this many followers are merged into feeds when they are read
+	MaxFanOutFollowers int `yaml:"max_fanout_followers" env:"TIMELINE_MAX_FANOUT_FOLLOWERS" env-default:"10000"`
+	BackfillPosts      int `yaml:"backfill_posts" env:"TIMELINE_BACKFILL_POSTS" env-default:"50"`
+	RebuildPosts       int `yaml:"rebuild_posts" env:"TIMELINE_REBUILD_POSTS" env-default:"500"`
+}
+
+type Trending struct {
+	// Interval is the time in seconds between two refreshes of the trending posts
+	Interval int `yaml:"interval" env:"TRENDING_INTERVAL" env-default:"300"`
+	// Window is the number of hours of reactions, comments, reposts and quotes that are counted
+	Window int `yaml:"window" env:"TRENDING_WINDOW" env-default:"24"`
+	Size   int `yaml:"size" env:"TRENDING_SIZE" env-default:"100"`
+}
+
+type Reactions struct {
+	// Emoji users can react with; 👍 is always allowed because likes are stored as it
+	Emoji []string `yaml:"emoji" env:"REACTION_EMOJI" env-separator:"," env-default:"👍,❤️,😂,😮,😢,🎉"`
+}
+
+type Comments struct {
+	// EditWindow is the number of minutes after writing a comment during which its author can edit it
+	EditWindow int `yaml:"edit_window" env:"COMMENT_EDIT_WINDOW" env-default:"15"`
+}
+
+type OIDC struct {
+	// ProviderNames lists the enabled providers. Each one is configured with
+	// OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID and OIDC_<NAME>_CLIENT_SECRET.
+	ProviderNames []string `yaml:"provider_names" env:"OIDC_PROVIDERS" env-separator:","`
+	// RedirectURL is the public base of the callback URLs, which end in /{provider}/callback
+	RedirectURL string                  `yaml:"redirect_url" env:"OIDC_REDIRECT_URL" env-default:"http://localhost:8080/auth/oidc"`
+	Providers   map[string]OIDCProvider `yaml:"providers"`
+}
+
+type OIDCProvider struct {
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
 }
 
 func MustLoad() *Config {
@@ -34,5 +134,39 @@ func MustLoad() *Config {
 		panic("config error: " + err.Error())
 	}
 
+	err = loadOIDCProviders(&cfg.OIDC)
+	if err != nil {
+		panic("config error: " + err.Error())
+	}
+
 	return &cfg
-}
\ No newline at end of file
+}
+
+// loadOIDCProviders reads the per-provider variables, whose names depend on
+// OIDC_PROVIDERS and so cannot be declared as struct tags.
+func loadOIDCProviders(cfg *OIDC) error {
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]OIDCProvider)
+	}
+
+	for _, name := range cfg.ProviderNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProvider{
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return fmt.Errorf("%sISSUER and %sCLIENT_ID are required", prefix, prefix)
+		}
+
+		cfg.Providers[name] = provider
+	}
+
+	return nil
+}
@@ -2,43 +2,132 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/usecase"
+	"social/api/pkg/jwt"
 )
 
 type contextKey string
 
-const UserContextKey contextKey = "userID"
-
-func Auth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// In a real implementation, you would validate the JWT token here
-		// For now, we'll just check for an Authorization header and extract a user ID
-		
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "authorization header required", http.StatusUnauthorized)
-			return
-		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			http.Error(w, "bearer token required", http.StatusUnauthorized)
-			return
-		}
-
-		// In a real implementation, you would parse and validate the JWT token
-		// For now, we'll just use a placeholder user ID
-		// In a real app, this would come from the validated token
-		userID, err := uuid.Parse("00000000-0000-0000-0000-000000000000")
-		if err != nil {
-			http.Error(w, "invalid user ID in token", http.StatusUnauthorized)
-			return
-		}
-
-		ctx := context.WithValue(r.Context(), UserContextKey, userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
-}
\ No newline at end of file
+const (
+	UserContextKey     contextKey = "userID"
+	UsernameContextKey contextKey = "username"
+	RolesContextKey    contextKey = "roles"
+	SessionContextKey  contextKey = "sessionID"
+	ScopesContextKey   contextKey = "scopes"
+)
+
+// Auth accepts either a JWT access token from the login flow or a personal
+// access token. Only the latter carries scopes in the request context. JWTs
+// are only accepted while their session is live, so they stop working as
+// soon as the session is logged out or revoked.
+func Auth(tokens *jwt.Manager, sessions usecase.Session, personalTokens usecase.Token) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				writeError(w, http.StatusUnauthorized, "unauthorized", "authorization header required")
+				return
+			}
+
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+			if tokenString == authHeader {
+				writeError(w, http.StatusUnauthorized, "unauthorized", "bearer token required")
+				return
+			}
+
+			if strings.HasPrefix(tokenString, entity.PersonalTokenPrefix) {
+				user, token, err := personalTokens.Authenticate(r.Context(), tokenString)
+				if err != nil {
+					unauthorized(w, err)
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserContextKey, user.ID)
+				ctx = context.WithValue(ctx, UsernameContextKey, user.Username)
+				ctx = context.WithValue(ctx, RolesContextKey, user.Roles)
+				ctx = context.WithValue(ctx, ScopesContextKey, token.Scopes)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims, err := tokens.Parse(tokenString)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			// Purpose-bound tokens such as pending 2FA challenges are not access tokens
+			if claims.Purpose != "" {
+				unauthorized(w, jwt.ErrMalformedToken)
+				return
+			}
+
+			userID, err := uuid.Parse(claims.Subject)
+			if err != nil {
+				unauthorized(w, jwt.ErrMalformedToken)
+				return
+			}
+
+			sessionID, err := uuid.Parse(claims.SessionID)
+			if err != nil {
+				unauthorized(w, jwt.ErrMalformedToken)
+				return
+			}
+
+			err = sessions.Authenticate(r.Context(), sessionID, userID)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, userID)
+			ctx = context.WithValue(ctx, UsernameContextKey, claims.Username)
+			ctx = context.WithValue(ctx, RolesContextKey, claims.Roles)
+			ctx = context.WithValue(ctx, SessionContextKey, sessionID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuth authenticates requests that carry a token just like Auth, but
+// lets requests without an Authorization header through anonymously.
+func OptionalAuth(tokens *jwt.Manager, sessions usecase.Session, personalTokens usecase.Token) func(http.Handler) http.Handler {
+	auth := Auth(tokens, sessions, personalTokens)
+	return func(next http.Handler) http.Handler {
+		authenticated := auth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// unauthorized reports why a token was rejected so clients can tell an
+// expired session apart from a forged or foreign token.
+func unauthorized(w http.ResponseWriter, err error) {
+	code, message := "invalid_token", "invalid token"
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired), errors.Is(err, usecase.ErrAccessTokenExpired):
+		code, message = "token_expired", "token expired"
+	case errors.Is(err, usecase.ErrAccessTokenRevoked):
+		code, message = "token_revoked", "token revoked"
+	case errors.Is(err, jwt.ErrInvalidSignature):
+		message = "invalid token signature"
+	case errors.Is(err, jwt.ErrInvalidIssuer):
+		message = "invalid token issuer"
+	case errors.Is(err, jwt.ErrMalformedToken):
+		message = "malformed token"
+	}
+
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+message+`"`)
+	writeError(w, http.StatusUnauthorized, code, message)
+}
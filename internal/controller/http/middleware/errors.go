@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError answers in the same error envelope as the v1 handlers.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]errorBody{
+		"error": {Code: code, Message: message},
+	})
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"social/api/internal/entity"
+)
+
+// RequireRole lets a request through if the caller has at least one of roles.
+// Roles come from the access token, so a change takes effect on the next refresh.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			have, _ := r.Context().Value(RolesContextKey).([]string)
+			if !entity.HasAnyRole(have, roles...) {
+				writeError(w, http.StatusForbidden, "forbidden", "forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
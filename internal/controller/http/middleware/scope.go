@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+)
+
+// RequireScope rejects personal access tokens that were not granted scope.
+// Requests authenticated through the login flow have no scope restrictions.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, ok := r.Context().Value(ScopesContextKey).([]string)
+			if ok && !slices.Contains(scopes, scope) {
+				writeError(w, http.StatusForbidden, "insufficient_scope", "token is missing scope "+scope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession restricts a route to logged-in sessions, so personal access
+// tokens cannot be used to manage sessions or mint further tokens.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(ScopesContextKey).([]string); ok {
+			writeError(w, http.StatusForbidden, "session_required", "personal access tokens cannot be used here")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
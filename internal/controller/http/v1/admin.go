@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"social/api/internal/entity"
+)
+
+type setRolesRequest struct {
+	Roles []string `json:"roles" validate:"required"`
+}
+
+type adminUserResponse struct {
+	User
+	Roles []string `json:"roles"`
+}
+
+func (h *Handler) getUserAsAdmin(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	user, err := h.userUseCase.GetProfile(r.Context(), username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeAdminUser(w, user)
+}
+
+func (h *Handler) setUserRoles(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	var req setRolesRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := h.userUseCase.SetRoles(r.Context(), username, req.Roles)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeAdminUser(w, user)
+}
+
+func writeAdminUser(w http.ResponseWriter, user *entity.User) {
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	response := adminUserResponse{
+		User: User{
+			ID:            user.ID.String(),
+			Name:          user.Name,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: emailVerified(user),
+			Bio:           user.Bio,
+			ImageURL:      user.ImageURL,
+			CreatedAt:     user.CreatedAt.String(),
+			UpdatedAt:     user.UpdatedAt.String(),
+		},
+		Roles: roles,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
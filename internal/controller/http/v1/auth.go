@@ -2,7 +2,15 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
+	"social/api/internal/usecase"
 )
 
 type registerRequest struct {
@@ -17,55 +25,70 @@ type loginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
 type authResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    string `json:"expires_at"`
+	User         User   `json:"user"`
+}
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    string `json:"expires_at"`
 }
 
 type User struct {
-	ID        string  `json:"id"`
-	Name      string  `json:"name"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	Bio       *string `json:"bio,omitempty"`
-	ImageURL  *string `json:"image_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// EmailVerified is only set on responses about the caller's own account
+	EmailVerified *bool   `json:"email_verified,omitempty"`
+	PrivateLikes  *bool   `json:"private_likes,omitempty"`
+	Bio           *string `json:"bio,omitempty"`
+	ImageURL      *string `json:"image_url,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
 }
 
 func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 	var req registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	// In a real implementation, you would validate the request fields here
-
 	user, err := h.userUseCase.Register(r.Context(), req.Name, req.Username, req.Email, req.Password)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
-	// In a real implementation, you would generate a real JWT token here
-	token, err := h.userUseCase.Login(r.Context(), req.Email, req.Password)
+	result, err := h.userUseCase.Login(r.Context(), req.Email, req.Password, clientFromRequest(r))
 	if err != nil {
-		http.Error(w, "failed to generate token", http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
 	response := authResponse{
-		Token: token,
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+		ExpiresAt:    result.Tokens.ExpiresAt.Format(time.RFC3339),
 		User: User{
-			ID:        user.ID.String(),
-			Name:      user.Name,
-			Username:  user.Username,
-			Email:     user.Email,
-			Bio:       user.Bio,
-			ImageURL:  user.ImageURL,
-			CreatedAt: user.CreatedAt.String(),
-			UpdatedAt: user.UpdatedAt.String(),
+			ID:            user.ID.String(),
+			Name:          user.Name,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: emailVerified(user),
+			Bio:           user.Bio,
+			ImageURL:      user.ImageURL,
+			CreatedAt:     user.CreatedAt.String(),
+			UpdatedAt:     user.UpdatedAt.String(),
 		},
 	}
 
@@ -76,29 +99,119 @@ func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	token, err := h.userUseCase.Login(r.Context(), req.Email, req.Password)
+	result, err := h.userUseCase.Login(r.Context(), req.Email, req.Password, clientFromRequest(r))
 	if err != nil {
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		writeError(w, err)
+		return
+	}
+
+	h.writeLoginResult(w, result)
+}
+
+// writeLoginResult answers with session tokens, or with a 2FA challenge for
+// accounts that still have to pass LoginMFA.
+func (h *Handler) writeLoginResult(w http.ResponseWriter, result *entity.LoginResult) {
+	if result.Tokens == nil {
+		response := mfaChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAToken,
+			ExpiresAt:   result.MFAExpiresAt.Format(time.RFC3339),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	// In a real implementation, you would get the user details from the token
-	// For now, we'll just return a placeholder
+	h.writeAuthResponse(w, result.User, result.Tokens)
+}
+
+func (h *Handler) writeAuthResponse(w http.ResponseWriter, user *entity.User, tokens *entity.AuthTokens) {
 	response := authResponse{
-		Token: token,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt.Format(time.RFC3339),
 		User: User{
-			ID:       "00000000-0000-0000-0000-000000000000",
-			Name:     "John Doe",
-			Username: "johndoe",
-			Email:    req.Email,
+			ID:            user.ID.String(),
+			Name:          user.Name,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: emailVerified(user),
+			Bio:           user.Bio,
+			ImageURL:      user.ImageURL,
+			CreatedAt:     user.CreatedAt.String(),
+			UpdatedAt:     user.UpdatedAt.String(),
 		},
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	tokens, err := h.sessionUseCase.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := tokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt.Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) logout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	sessionID, ok := r.Context().Value(middleware.SessionContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errNoSession)
+		return
+	}
+
+	err := h.sessionUseCase.Logout(r.Context(), sessionID, userID)
+	if err != nil && !errors.Is(err, usecase.ErrSessionNotFound) {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func emailVerified(user *entity.User) *bool {
+	verified := user.EmailVerifiedAt != nil
+	return &verified
+}
+
+// clientFromRequest describes the calling device for session bookkeeping.
+func clientFromRequest(r *http.Request) entity.Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return entity.Client{
+		UserAgent: r.UserAgent(),
+		IPAddress: ip,
+	}
+}
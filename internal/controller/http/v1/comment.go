@@ -7,18 +7,42 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
 )
 
 type addCommentRequest struct {
+	Content         string     `json:"content" validate:"required"`
+	ParentCommentID *uuid.UUID `json:"parent_comment_id,omitempty"`
+}
+
+type updateCommentRequest struct {
 	Content string `json:"content" validate:"required"`
 }
 
 type Comment struct {
-	ID        string `json:"id"`
-	PostID    string `json:"post_id"`
-	AuthorID  string `json:"author_id"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"`
+	ID     string `json:"id"`
+	PostID string `json:"post_id"`
+	// AuthorID is left out of the placeholders of deleted comments
+	AuthorID        string  `json:"author_id,omitempty"`
+	ParentCommentID *string `json:"parent_comment_id,omitempty"`
+	Depth           int     `json:"depth"`
+	ReplyCount      int     `json:"reply_count"`
+	Content         string  `json:"content"`
+	Deleted         bool    `json:"deleted"`
+	Edited          bool    `json:"edited"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+type CommentRevision struct {
+	ID         string `json:"id"`
+	Content    string `json:"content"`
+	CreatedAt  string `json:"created_at"`
+	ReplacedAt string `json:"replaced_at"`
+}
+
+type commentRevisionsResponse struct {
+	Revisions []CommentRevision `json:"revisions"`
 }
 
 type commentResponse struct {
@@ -26,48 +50,43 @@ type commentResponse struct {
 }
 
 type commentsResponse struct {
-	Comments []Comment `json:"comments"`
+	Comments   []Comment `json:"comments"`
+	NextCursor *string   `json:"next_cursor"`
 }
 
 func (h *Handler) addComment(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
 	var req addCommentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	comment, err := h.commentUseCase.AddComment(r.Context(), postID, userID, req.Content)
+	comment, err := h.commentUseCase.AddComment(r.Context(), postID, userID, req.Content, req.ParentCommentID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	response := commentResponse{
-		Comment: Comment{
-			ID:        comment.ID.String(),
-			PostID:    comment.PostID.String(),
-			AuthorID:  comment.AuthorID.String(),
-			Content:   comment.Content,
-			CreatedAt: comment.CreatedAt.String(),
-		},
+		Comment: newComment(comment),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -78,35 +97,155 @@ func (h *Handler) addComment(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getComments(w http.ResponseWriter, r *http.Request) {
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
-	comments, err := h.commentUseCase.GetComments(r.Context(), postID)
+	limit, cursor, err := pageParams(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
+		return
+	}
+
+	comments, next, err := h.commentUseCase.GetComments(r.Context(), postID, limit, cursor)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
 	responseComments := make([]Comment, len(comments))
 	for i, comment := range comments {
-		responseComments[i] = Comment{
-			ID:        comment.ID.String(),
-			PostID:    comment.PostID.String(),
-			AuthorID:  comment.AuthorID.String(),
-			Content:   comment.Content,
-			CreatedAt: comment.CreatedAt.String(),
-		}
+		responseComments[i] = newComment(&comment)
+	}
+
+	response := commentsResponse{
+		Comments:   responseComments,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) getCommentThread(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		writeError(w, paramError("commentID", "invalid comment ID"))
+		return
+	}
+
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	comments, next, err := h.commentUseCase.GetThread(r.Context(), postID, commentID, limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseComments := make([]Comment, len(comments))
+	for i, comment := range comments {
+		responseComments[i] = newComment(&comment)
 	}
 
 	response := commentsResponse{
-		Comments: responseComments,
+		Comments:   responseComments,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) updateComment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		writeError(w, paramError("commentID", "invalid comment ID"))
+		return
+	}
+
+	var req updateCommentRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	comment, err := h.commentUseCase.EditComment(r.Context(), postID, commentID, userID, req.Content)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := commentResponse{
+		Comment: newComment(comment),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) getCommentRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		writeError(w, paramError("commentID", "invalid comment ID"))
+		return
+	}
+
+	revisions, err := h.commentUseCase.GetCommentRevisions(r.Context(), postID, commentID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := commentRevisionsResponse{
+		Revisions: make([]CommentRevision, len(revisions)),
+	}
+	for i, revision := range revisions {
+		response.Revisions[i] = CommentRevision{
+			ID:         revision.ID.String(),
+			Content:    revision.Content,
+			CreatedAt:  revision.CreatedAt.String(),
+			ReplacedAt: revision.ReplacedAt.String(),
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -116,27 +255,55 @@ func (h *Handler) getComments(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) deleteComment(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
 	commentIDStr := chi.URLParam(r, "commentID")
 	if commentIDStr == "" {
-		http.Error(w, "comment ID is required", http.StatusBadRequest)
+		writeError(w, paramError("commentID", "comment ID is required"))
 		return
 	}
 
 	commentID, err := uuid.Parse(commentIDStr)
 	if err != nil {
-		http.Error(w, "invalid comment ID", http.StatusBadRequest)
+		writeError(w, paramError("commentID", "invalid comment ID"))
 		return
 	}
 
-	err = h.commentUseCase.DeleteComment(r.Context(), commentID, userID)
+	err = h.commentUseCase.DeleteComment(r.Context(), postID, commentID, userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+func newComment(comment *entity.Comment) Comment {
+	response := Comment{
+		ID:         comment.ID.String(),
+		PostID:     comment.PostID.String(),
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		Content:    comment.Content,
+		Deleted:    comment.IsDeleted(),
+		CreatedAt:  comment.CreatedAt.String(),
+		UpdatedAt:  comment.UpdatedAt.String(),
+	}
+	if !comment.IsDeleted() {
+		response.AuthorID = comment.AuthorID.String()
+		response.Edited = comment.IsEdited()
+	}
+	if comment.ParentCommentID != nil {
+		id := comment.ParentCommentID.String()
+		response.ParentCommentID = &id
+	}
+	return response
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
+)
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
+
+type changeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type changeUsernameRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+func (h *Handler) changePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req changePasswordRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Personal access tokens have no session, so every session is revoked
+	sessionID, _ := r.Context().Value(middleware.SessionContextKey).(uuid.UUID)
+
+	err := h.userUseCase.ChangePassword(r.Context(), userID, sessionID, req.CurrentPassword, req.NewPassword, clientFromRequest(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) changeEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req changeEmailRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := h.userUseCase.ChangeEmail(r.Context(), userID, req.Password, req.Email, clientFromRequest(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeOwnProfile(w, user)
+}
+
+func (h *Handler) changeUsername(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req changeUsernameRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := h.userUseCase.ChangeUsername(r.Context(), userID, req.Username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeOwnProfile(w, user)
+}
+
+func writeOwnProfile(w http.ResponseWriter, user *entity.User) {
+	response := User{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: emailVerified(user),
+		Bio:           user.Bio,
+		ImageURL:      user.ImageURL,
+		CreatedAt:     user.CreatedAt.String(),
+		UpdatedAt:     user.UpdatedAt.String(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
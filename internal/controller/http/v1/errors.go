@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"social/api/internal/usecase"
+)
+
+var (
+	errUnauthorized   = usecase.NewError(usecase.ErrUnauthorized, "unauthorized", "unauthorized")
+	errInvalidBody    = usecase.NewError(usecase.ErrValidation, "invalid_body", "invalid request body")
+	errNoSession      = usecase.NewError(usecase.ErrValidation, "no_session", "token is not bound to a session")
+	errDebugForbidden = usecase.NewError(usecase.ErrForbidden, "debug_forbidden", "only admins can debug the feed ranking")
+)
+
+// kindStatus maps the use case error kinds to HTTP statuses and fallback codes.
+var kindStatus = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{usecase.ErrNotFound, http.StatusNotFound, "not_found"},
+	{usecase.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{usecase.ErrConflict, http.StatusConflict, "conflict"},
+	{usecase.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{usecase.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{usecase.ErrRateLimited, http.StatusTooManyRequests, "rate_limited"},
+	{errTooLarge, http.StatusRequestEntityTooLarge, "body_too_large"},
+}
+
+type errorBody struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// writeError is the single place errors leave the API. Use case errors are
+// reported with their code and message; anything unexpected is logged and
+// hidden behind a generic 500 so database errors never reach clients.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	body := errorBody{Code: "internal_error", Message: "internal server error"}
+
+	for _, k := range kindStatus {
+		if errors.Is(err, k.kind) {
+			status = k.status
+			body = errorBody{Code: k.code, Message: k.kind.Error()}
+			break
+		}
+	}
+
+	var typed *usecase.Error
+	if status != http.StatusInternalServerError && errors.As(err, &typed) {
+		body = errorBody{Code: typed.Code, Message: typed.Message, Details: typed.Details}
+	}
+
+	var lockout *usecase.LockoutError
+	if errors.As(err, &lockout) {
+		body = errorBody{Code: "login_locked", Message: lockout.Error()}
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+	}
+
+	if status == http.StatusInternalServerError {
+		log.Printf("internal error: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: body})
+}
+
+// paramError reports a missing or malformed path or query parameter.
+func paramError(name, message string) error {
+	return usecase.NewError(usecase.ErrValidation, "invalid_parameter", message).WithDetails(map[string]string{name: message})
+}
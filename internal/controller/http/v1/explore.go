@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type trendingResponse struct {
+	Posts []Post `json:"posts"`
+}
+
+func (h *Handler) getExplore(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	posts, next, err := h.postUseCase.GetExplore(r.Context(), viewerID(r), limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responsePosts := make([]Post, len(posts))
+	for i, post := range posts {
+		responsePosts[i] = newPost(&post)
+	}
+
+	response := postsResponse{
+		Posts:      responsePosts,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// getTrending is not paged: the ranking changes with every refresh, so a
+// cursor into it would not stay meaningful.
+func (h *Handler) getTrending(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	posts, err := h.postUseCase.GetTrending(r.Context(), viewerID(r), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responsePosts := make([]Post, len(posts))
+	for i, post := range posts {
+		responsePosts[i] = newPost(&post)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trendingResponse{Posts: responsePosts})
+}
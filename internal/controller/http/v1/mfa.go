@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+)
+
+type mfaLoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type mfaChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+type totpEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (h *Handler) loginMFA(w http.ResponseWriter, r *http.Request) {
+	var req mfaLoginRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, tokens, err := h.userUseCase.LoginMFA(r.Context(), req.MFAToken, req.Code, clientFromRequest(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.writeAuthResponse(w, user, tokens)
+}
+
+func (h *Handler) enrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	enrollment, err := h.userUseCase.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := totpEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) confirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req mfaCodeRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	codes, err := h.userUseCase.ConfirmTOTP(r.Context(), userID, req.Code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := recoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) disableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req mfaCodeRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err := h.userUseCase.DisableTOTP(r.Context(), userID, req.Code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
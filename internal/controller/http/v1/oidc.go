@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"social/api/internal/usecase"
+)
+
+const oidcStateCookie = "oidc_state"
+
+func (h *Handler) startOIDC(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	authURL, sealedState, err := h.oidcUseCase.Start(r.Context(), provider)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Scoped to this provider's callback and only readable by the server
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    sealedState,
+		Path:     "/auth/oidc/" + provider,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h *Handler) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	// The state is single-use, whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     "/auth/oidc/" + provider,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		writeError(w, usecase.ErrOIDCLoginFailed.WithDetails(map[string]string{"error": providerErr}))
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		writeError(w, usecase.ErrInvalidOIDCState)
+		return
+	}
+
+	result, err := h.oidcUseCase.Callback(r.Context(), provider, query.Get("code"), query.Get("state"), cookie.Value, clientFromRequest(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.writeLoginResult(w, result)
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req forgotPasswordRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err := h.passwordUseCase.ForgotPassword(r.Context(), req.Email)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Same answer whether or not the email is registered
+	response := messageResponse{
+		Message: "if an account exists for this email, a reset link has been sent",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err := h.passwordUseCase.ResetPassword(r.Context(), req.Token, req.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
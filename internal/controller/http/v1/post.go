@@ -3,24 +3,47 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
 )
 
 type createPostRequest struct {
+	Content   string     `json:"content" validate:"required"`
+	ImageURL  *string    `json:"image_url,omitempty" validate:"omitempty,url"`
+	QuoteOfID *uuid.UUID `json:"quote_of_id,omitempty"`
+}
+
+type updatePostRequest struct {
 	Content  string  `json:"content" validate:"required"`
-	ImageURL *string `json:"image_url,omitempty"`
+	ImageURL *string `json:"image_url,omitempty" validate:"omitempty,url"`
 }
 
 type Post struct {
-	ID        string  `json:"id"`
-	AuthorID  string  `json:"author_id"`
-	Content   string  `json:"content"`
-	ImageURL  *string `json:"image_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID           string  `json:"id"`
+	AuthorID     string  `json:"author_id"`
+	Author       *Actor  `json:"author,omitempty"`
+	Content      string  `json:"content"`
+	ImageURL     *string `json:"image_url,omitempty"`
+	RepostOfID   *string `json:"repost_of_id,omitempty"`
+	QuoteOfID    *string `json:"quote_of_id,omitempty"`
+	LikeCount    int     `json:"like_count"`
+	CommentCount int     `json:"comment_count"`
+	RepostCount  int     `json:"repost_count"`
+	QuoteCount   int     `json:"quote_count"`
+	// LikedByMe and RepostedByMe are always false for anonymous requests
+	LikedByMe    bool       `json:"liked_by_me"`
+	RepostedByMe bool       `json:"reposted_by_me"`
+	Reactions    *Reactions `json:"reactions,omitempty"`
+	// Original is the reposted or quoted post. OriginalDeleted is set instead
+	// once that post is deleted.
+	Original        *Post  `json:"original,omitempty"`
+	OriginalDeleted bool   `json:"original_deleted,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
 }
 
 type postResponse struct {
@@ -28,37 +51,67 @@ type postResponse struct {
 }
 
 type postsResponse struct {
-	Posts []Post `json:"posts"`
+	Posts      []Post  `json:"posts"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// Actor is the public part of a user shown next to posts and feed items.
+type Actor struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Name     string  `json:"name"`
+	ImageURL *string `json:"image_url,omitempty"`
+}
+
+type FeedItem struct {
+	Type  string `json:"type"`
+	Actor Actor  `json:"actor"`
+	Post  Post   `json:"post"`
+	// Ranking is only set on For You feeds requested with debug=true
+	Ranking *Ranking `json:"ranking,omitempty"`
+}
+
+type Ranking struct {
+	Score    float64            `json:"score"`
+	Features map[string]float64 `json:"features"`
+}
+
+type Liker struct {
+	User    Actor  `json:"user"`
+	LikedAt string `json:"liked_at"`
+}
+
+type likersResponse struct {
+	Likes      []Liker `json:"likes"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+type feedResponse struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor *string    `json:"next_cursor"`
 }
 
 func (h *Handler) createPost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	var req createPostRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	post, err := h.postUseCase.CreatePost(r.Context(), userID, req.Content, req.ImageURL)
+	post, err := h.postUseCase.CreatePost(r.Context(), userID, req.Content, req.ImageURL, req.QuoteOfID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	response := postResponse{
-		Post: Post{
-			ID:        post.ID.String(),
-			AuthorID:  post.AuthorID.String(),
-			Content:   post.Content,
-			ImageURL:  post.ImageURL,
-			CreatedAt: post.CreatedAt.String(),
-			UpdatedAt: post.UpdatedAt.String(),
-		},
+		Post: newPost(post),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -69,31 +122,24 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getPostByID(w http.ResponseWriter, r *http.Request) {
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
-	post, err := h.postUseCase.GetPostByID(r.Context(), postID)
+	post, err := h.postUseCase.GetPostByID(r.Context(), postID, viewerID(r))
 	if err != nil {
-		http.Error(w, "post not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
 	response := postResponse{
-		Post: Post{
-			ID:        post.ID.String(),
-			AuthorID:  post.AuthorID.String(),
-			Content:   post.Content,
-			ImageURL:  post.ImageURL,
-			CreatedAt: post.CreatedAt.String(),
-			UpdatedAt: post.UpdatedAt.String(),
-		},
+		Post: newPost(post),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -103,30 +149,30 @@ func (h *Handler) getPostByID(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getPostsByUser(w http.ResponseWriter, r *http.Request) {
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
 		return
 	}
 
-	posts, err := h.postUseCase.GetPostsByUser(r.Context(), username)
+	limit, cursor, err := pageParams(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, err)
+		return
+	}
+
+	posts, next, err := h.postUseCase.GetPostsByUser(r.Context(), username, viewerID(r), limit, cursor)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
 	responsePosts := make([]Post, len(posts))
 	for i, post := range posts {
-		responsePosts[i] = Post{
-			ID:        post.ID.String(),
-			AuthorID:  post.AuthorID.String(),
-			Content:   post.Content,
-			ImageURL:  post.ImageURL,
-			CreatedAt: post.CreatedAt.String(),
-			UpdatedAt: post.UpdatedAt.String(),
-		}
+		responsePosts[i] = newPost(&post)
 	}
 
 	response := postsResponse{
-		Posts: responsePosts,
+		Posts:      responsePosts,
+		NextCursor: nextCursor(next),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -136,43 +182,36 @@ func (h *Handler) getPostsByUser(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) updatePost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
-	var req createPostRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	var req updatePostRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
 	post, err := h.postUseCase.UpdatePost(r.Context(), postID, userID, req.Content, req.ImageURL)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	response := postResponse{
-		Post: Post{
-			ID:        post.ID.String(),
-			AuthorID:  post.AuthorID.String(),
-			Content:   post.Content,
-			ImageURL:  post.ImageURL,
-			CreatedAt: post.CreatedAt.String(),
-			UpdatedAt: post.UpdatedAt.String(),
-		},
+		Post: newPost(post),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -182,25 +221,74 @@ func (h *Handler) updatePost(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
 	err = h.postUseCase.DeletePost(r.Context(), postID, userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) repostPost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	repost, err := h.postUseCase.Repost(r.Context(), postID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := postResponse{
+		Post: newPost(repost),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) unrepostPost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	err = h.postUseCase.Unrepost(r.Context(), postID, userID)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -210,58 +298,205 @@ func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getFeed(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
-	posts, err := h.postUseCase.GetFeed(r.Context(), userID)
+	limit, cursor, err := pageParams(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	responsePosts := make([]Post, len(posts))
-	for i, post := range posts {
-		responsePosts[i] = Post{
-			ID:        post.ID.String(),
-			AuthorID:  post.AuthorID.String(),
-			Content:   post.Content,
-			ImageURL:  post.ImageURL,
-			CreatedAt: post.CreatedAt.String(),
-			UpdatedAt: post.UpdatedAt.String(),
+	items, next, err := h.postUseCase.GetFeed(r.Context(), userID, limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseItems := make([]FeedItem, len(items))
+	for i, item := range items {
+		responseItems[i] = feedItem(item)
+	}
+
+	response := feedResponse{
+		Items:      responseItems,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) getForYouFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	debug := false
+	if s := r.URL.Query().Get("debug"); s != "" {
+		debug, err = strconv.ParseBool(s)
+		if err != nil {
+			writeError(w, paramError("debug", "debug must be true or false"))
+			return
 		}
 	}
 
-	response := postsResponse{
-		Posts: responsePosts,
+	// Scores reveal how the ranking works, so only admins get to see them
+	roles, _ := r.Context().Value(middleware.RolesContextKey).([]string)
+	if debug && !entity.HasAnyRole(roles, entity.RoleAdmin) {
+		writeError(w, errDebugForbidden)
+		return
+	}
+
+	items, next, err := h.postUseCase.GetForYouFeed(r.Context(), userID, limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseItems := make([]FeedItem, len(items))
+	for i, item := range items {
+		responseItems[i] = feedItem(item.FeedItem)
+		if debug {
+			responseItems[i].Ranking = &Ranking{
+				Score:    item.Score,
+				Features: item.Features,
+			}
+		}
+	}
+
+	response := feedResponse{
+		Items:      responseItems,
+		NextCursor: nextCursor(next),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) getPostLikers(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		writeError(w, paramError("postID", "invalid post ID"))
+		return
+	}
+
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	likers, next, err := h.interactionUseCase.GetPostLikers(r.Context(), postID, viewerID(r), limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseLikers := make([]Liker, len(likers))
+	for i, liker := range likers {
+		responseLikers[i] = Liker{
+			User:    newActor(&liker.User),
+			LikedAt: liker.LikedAt.String(),
+		}
+	}
+
+	response := likersResponse{
+		Likes:      responseLikers,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func feedItem(item entity.FeedItem) FeedItem {
+	return FeedItem{
+		Type:  string(item.Type),
+		Actor: newActor(&item.Actor),
+		Post:  newPost(&item.Post),
+	}
+}
+
+func newPost(post *entity.Post) Post {
+	response := Post{
+		ID:              post.ID.String(),
+		AuthorID:        post.AuthorID.String(),
+		Content:         post.Content,
+		ImageURL:        post.ImageURL,
+		LikeCount:       post.LikeCount,
+		CommentCount:    post.CommentCount,
+		RepostCount:     post.RepostCount,
+		QuoteCount:      post.QuoteCount,
+		LikedByMe:       post.LikedByMe,
+		RepostedByMe:    post.RepostedByMe,
+		OriginalDeleted: post.OriginalDeleted,
+		CreatedAt:       post.CreatedAt.String(),
+		UpdatedAt:       post.UpdatedAt.String(),
+	}
+	if post.RepostOfID != nil {
+		id := post.RepostOfID.String()
+		response.RepostOfID = &id
+	}
+	if post.QuoteOfID != nil {
+		id := post.QuoteOfID.String()
+		response.QuoteOfID = &id
+	}
+	if post.Original != nil {
+		original := newPost(post.Original)
+		response.Original = &original
+	}
+	if post.Author != nil {
+		author := newActor(post.Author)
+		response.Author = &author
+	}
+	if post.Reactions != nil {
+		reactions := newReactions(post.Reactions)
+		response.Reactions = &reactions
+	}
+	return response
+}
+
+func newActor(user *entity.User) Actor {
+	return Actor{
+		ID:       user.ID.String(),
+		Username: user.Username,
+		Name:     user.Name,
+		ImageURL: user.ImageURL,
+	}
+}
+
 func (h *Handler) likePost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
 	err = h.interactionUseCase.LikePost(r.Context(), postID, userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -271,27 +506,27 @@ func (h *Handler) likePost(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) unlikePost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	postIDStr := chi.URLParam(r, "postID")
 	if postIDStr == "" {
-		http.Error(w, "post ID is required", http.StatusBadRequest)
+		writeError(w, paramError("postID", "post ID is required"))
 		return
 	}
 
 	postID, err := uuid.Parse(postIDStr)
 	if err != nil {
-		http.Error(w, "invalid post ID", http.StatusBadRequest)
+		writeError(w, paramError("postID", "invalid post ID"))
 		return
 	}
 
 	err = h.interactionUseCase.UnlikePost(r.Context(), postID, userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
-}
\ No newline at end of file
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
+)
+
+// Reactions summarizes the reactions to a post or comment.
+type Reactions struct {
+	Counts map[string]int `json:"counts"`
+	// Mine is always empty for anonymous requests
+	Mine []string `json:"mine"`
+}
+
+type reactionsResponse struct {
+	Reactions Reactions `json:"reactions"`
+}
+
+func (h *Handler) addReaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	target, err := reactionTarget(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	emoji, err := reactionEmoji(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err = h.reactionUseCase.AddReaction(r.Context(), userID, target, emoji)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) removeReaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	target, err := reactionTarget(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	emoji, err := reactionEmoji(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err = h.reactionUseCase.RemoveReaction(r.Context(), userID, target, emoji)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getReactions(w http.ResponseWriter, r *http.Request) {
+	target, err := reactionTarget(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	summary, err := h.reactionUseCase.GetReactionSummary(r.Context(), viewerID(r), target)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactionsResponse{Reactions: newReactions(summary)})
+}
+
+// reactionTarget reads the post and, on comment routes, the comment the
+// request reacts to.
+func reactionTarget(r *http.Request) (entity.ReactionTarget, error) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		return entity.ReactionTarget{}, paramError("postID", "invalid post ID")
+	}
+
+	target := entity.ReactionTarget{PostID: postID}
+
+	commentIDStr := chi.URLParam(r, "commentID")
+	if commentIDStr != "" {
+		commentID, err := uuid.Parse(commentIDStr)
+		if err != nil {
+			return entity.ReactionTarget{}, paramError("commentID", "invalid comment ID")
+		}
+		target.CommentID = &commentID
+	}
+
+	return target, nil
+}
+
+// reactionEmoji reads the emoji, which clients send percent-encoded.
+func reactionEmoji(r *http.Request) (string, error) {
+	emoji, err := url.PathUnescape(chi.URLParam(r, "emoji"))
+	if err != nil || emoji == "" {
+		return "", paramError("emoji", "invalid emoji")
+	}
+	return emoji, nil
+}
+
+func newReactions(summary *entity.ReactionSummary) Reactions {
+	return Reactions{
+		Counts: summary.Counts,
+		Mine:   summary.Mine,
+	}
+}
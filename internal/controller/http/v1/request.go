@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+	"social/api/internal/usecase"
+)
+
+// maxBodyBytes caps request bodies; every request this API accepts is small.
+const maxBodyBytes = 1 << 20
+
+var (
+	errTooLarge     = errors.New("request body too large")
+	errBodyTooLarge = usecase.NewError(errTooLarge, "body_too_large", fmt.Sprintf("request body must be at most %d bytes", maxBodyBytes))
+)
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON names, which is what clients send
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	return v
+}
+
+// decode reads a single JSON object into dst and validates it against its
+// validate tags. Unknown fields and oversized bodies are rejected.
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err != nil {
+		return decodeError(err)
+	}
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return errInvalidBody.WithDetails(map[string]string{"body": "must contain a single JSON object"})
+	}
+
+	err = h.validate.Struct(dst)
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		details := make(map[string]string, len(invalid))
+		for _, fe := range invalid {
+			details[fe.Field()] = describe(fe)
+		}
+		return usecase.NewError(usecase.ErrValidation, "validation_failed", "request validation failed").WithDetails(details)
+	}
+
+	return err
+}
+
+func decodeError(err error) error {
+	var tooLarge *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &tooLarge):
+		return errBodyTooLarge
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return errInvalidBody.WithDetails(map[string]string{field: "must be a " + typeErr.Type.String()})
+	case errors.As(err, &syntaxErr):
+		return errInvalidBody.WithDetails(map[string]string{"body": fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)})
+	case errors.Is(err, io.EOF):
+		return errInvalidBody.WithDetails(map[string]string{"body": "must not be empty"})
+	}
+
+	// encoding/json has no typed error for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return errInvalidBody.WithDetails(map[string]string{strings.Trim(field, `"`): "unknown field"})
+	}
+
+	return errInvalidBody
+}
+
+// describe turns a failed validate tag into a message for clients.
+func describe(fe validator.FieldError) string {
+	unit := "characters"
+	if kind := fe.Kind(); kind == reflect.Slice || kind == reflect.Map || kind == reflect.Array {
+		unit = "items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "min":
+		return fmt.Sprintf("must be at least %s %s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s %s", fe.Param(), unit)
+	case "oneof":
+		return "must be one of " + fe.Param()
+	}
+
+	return "is invalid"
+}
+
+// pageParams reads the limit and cursor query parameters of list endpoints.
+func pageParams(r *http.Request) (int, string, error) {
+	query := r.URL.Query()
+
+	limit := 0
+	if s := query.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, "", paramError("limit", "limit must be a number")
+		}
+		limit = n
+	}
+
+	return limit, query.Get("cursor"), nil
+}
+
+// nextCursor renders the cursor of the next page as null on the last page.
+func nextCursor(cursor string) *string {
+	if cursor == "" {
+		return nil
+	}
+
+	return &cursor
+}
+
+// viewerID returns the logged-in user on routes that also serve anonymous
+// requests, or uuid.Nil if there is none.
+func viewerID(r *http.Request) uuid.UUID {
+	userID, _ := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	return userID
+}
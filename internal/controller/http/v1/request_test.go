@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeTestRequest struct {
+	Name  string   `json:"name" validate:"required,max=5"`
+	Age   int      `json:"age"`
+	Email string   `json:"email" validate:"omitempty,email"`
+	Tags  []string `json:"tags" validate:"max=2"`
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantCode    string
+		wantDetails map[string]string
+	}{
+		{
+			name: "valid",
+			body: `{"name": "ana", "age": 30, "email": "ana@example.com", "tags": ["a"]}`,
+		},
+		{
+			name:        "empty body",
+			body:        "",
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"body": "must not be empty"},
+		},
+		{
+			name:        "malformed JSON",
+			body:        `{"name": ana}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"body": "malformed JSON at offset 10"},
+		},
+		{
+			name:       "truncated JSON",
+			body:       `{"name": "ana"`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_body",
+		},
+		{
+			name:        "wrong type",
+			body:        `{"name": "ana", "age": "thirty"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"age": "must be a int"},
+		},
+		{
+			name:        "not an object",
+			body:        `["ana"]`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"body": "must be a v1.decodeTestRequest"},
+		},
+		{
+			name:        "unknown field",
+			body:        `{"name": "ana", "nickname": "an"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"nickname": "unknown field"},
+		},
+		{
+			name:        "two objects",
+			body:        `{"name": "ana"} {"name": "bo"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "invalid_body",
+			wantDetails: map[string]string{"body": "must contain a single JSON object"},
+		},
+		{
+			name:       "too large",
+			body:       `{"name": "` + strings.Repeat("a", maxBodyBytes) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "body_too_large",
+		},
+		{
+			name:        "missing required field",
+			body:        `{"age": 30}`,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "validation_failed",
+			wantDetails: map[string]string{"name": "is required"},
+		},
+		{
+			name:       "several invalid fields",
+			body:       `{"name": "anastasia", "email": "ana", "tags": ["a", "b", "c"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "validation_failed",
+			wantDetails: map[string]string{
+				"name":  "must be at most 5 characters",
+				"email": "must be a valid email address",
+				"tags":  "must be at most 2 items",
+			},
+		},
+	}
+
+	h := &Handler{validate: newValidator()}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var dst decodeTestRequest
+			err := h.decode(rec, r, &dst)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("decode() error = %v", err)
+				}
+				if dst.Name != "ana" || dst.Age != 30 {
+					t.Errorf("decoded %+v", dst)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("decode() error = nil")
+			}
+
+			writeError(rec, err)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", resp.Error.Code, tt.wantCode)
+			}
+			if !maps.Equal(resp.Error.Details, tt.wantDetails) {
+				t.Errorf("details = %v, want %v", resp.Error.Details, tt.wantDetails)
+			}
+		})
+	}
+}
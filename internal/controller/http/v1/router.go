@@ -2,23 +2,42 @@ package v1
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
 	"social/api/internal/usecase"
+	"social/api/pkg/jwt"
 )
 
 type Handler struct {
-	userUseCase        usecase.User
-	postUseCase        usecase.Post
-	commentUseCase     usecase.Comment
-	interactionUseCase usecase.Interaction
+	userUseCase         usecase.User
+	sessionUseCase      usecase.Session
+	passwordUseCase     usecase.Password
+	verificationUseCase usecase.Verification
+	oidcUseCase         usecase.OIDC
+	tokenUseCase        usecase.Token
+	postUseCase         usecase.Post
+	commentUseCase      usecase.Comment
+	interactionUseCase  usecase.Interaction
+	reactionUseCase     usecase.Reaction
+	tokens              *jwt.Manager
+	validate            *validator.Validate
 }
 
-func NewHandler(userUseCase usecase.User, postUseCase usecase.Post, commentUseCase usecase.Comment, interactionUseCase usecase.Interaction) *Handler {
+func NewHandler(userUseCase usecase.User, sessionUseCase usecase.Session, passwordUseCase usecase.Password, verificationUseCase usecase.Verification, oidcUseCase usecase.OIDC, tokenUseCase usecase.Token, postUseCase usecase.Post, commentUseCase usecase.Comment, interactionUseCase usecase.Interaction, reactionUseCase usecase.Reaction, tokens *jwt.Manager) *Handler {
 	return &Handler{
-		userUseCase:        userUseCase,
-		postUseCase:        postUseCase,
-		commentUseCase:     commentUseCase,
-		interactionUseCase: interactionUseCase,
+		userUseCase:         userUseCase,
+		sessionUseCase:      sessionUseCase,
+		passwordUseCase:     passwordUseCase,
+		verificationUseCase: verificationUseCase,
+		oidcUseCase:         oidcUseCase,
+		tokenUseCase:        tokenUseCase,
+		postUseCase:         postUseCase,
+		commentUseCase:      commentUseCase,
+		interactionUseCase:  interactionUseCase,
+		reactionUseCase:     reactionUseCase,
+		tokens:              tokens,
+		validate:            newValidator(),
 	}
 }
 
@@ -26,42 +45,138 @@ func (h *Handler) RegisterRoutes(r *chi.Mux) {
 	// Public routes
 	r.Post("/register", h.register)
 	r.Post("/login", h.login)
+	r.Post("/login/2fa", h.loginMFA)
+	r.Post("/auth/refresh", h.refresh)
+	r.Get("/auth/oidc/{provider}/start", h.startOIDC)
+	r.Get("/auth/oidc/{provider}/callback", h.oidcCallback)
+	r.Post("/password/forgot", h.forgotPassword)
+	r.Post("/password/reset", h.resetPassword)
+	r.Get("/verify-email", h.verifyEmail)
 
 	// User routes
 	r.Get("/users/{username}", h.getProfile)
 	r.Get("/users/search", h.searchUsers)
 
-	// Post routes
-	r.Get("/posts/{postID}", h.getPostByID)
-	r.Get("/users/{username}/posts", h.getPostsByUser)
+	// Post routes are public, but show logged-in users what they liked
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.OptionalAuth(h.tokens, h.sessionUseCase, h.tokenUseCase))
+
+		r.Get("/posts/{postID}", h.getPostByID)
+		r.Get("/users/{username}/posts", h.getPostsByUser)
+
+		// Like routes
+		r.Get("/posts/{postID}/likes", h.getPostLikers)
+		r.Get("/users/{username}/likes", h.getLikedPosts)
+
+		// Reaction routes
+		r.Get("/posts/{postID}/reactions", h.getReactions)
+		r.Get("/posts/{postID}/comments/{commentID}/reactions", h.getReactions)
+
+		// Discovery routes
+		r.Get("/explore", h.getExplore)
+		r.Get("/trending", h.getTrending)
+	})
 
 	// Protected routes
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Auth)
+		r.Use(middleware.Auth(h.tokens, h.sessionUseCase, h.tokenUseCase))
+
+		// Credential management is only available to logged-in sessions
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireSession)
+
+			// Session routes
+			r.Post("/logout", h.logout)
+			r.Get("/sessions", h.getSessions)
+			r.Delete("/sessions/{sessionID}", h.deleteSession)
+
+			// Email verification routes
+			r.Post("/verify-email/resend", h.resendVerification)
+
+			// Credential routes
+			r.Put("/profile/password", h.changePassword)
+			r.Put("/profile/email", h.changeEmail)
+			r.Put("/profile/username", h.changeUsername)
+
+			// Personal access token routes
+			r.Post("/profile/tokens", h.createToken)
+			r.Get("/profile/tokens", h.getTokens)
+			r.Delete("/profile/tokens/{tokenID}", h.deleteToken)
 
-		// User routes
-		r.Get("/profile", h.getMyProfile)
-		r.Put("/profile", h.updateProfile)
+			// Two-factor authentication routes
+			r.Post("/profile/2fa/enroll", h.enrollTOTP)
+			r.Post("/profile/2fa/confirm", h.confirmTOTP)
+			r.Post("/profile/2fa/disable", h.disableTOTP)
+		})
+
+		// Admin routes
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireSession)
+			r.Use(middleware.RequireRole(entity.RoleAdmin))
+
+			r.Get("/users/{username}", h.getUserAsAdmin)
+			r.Put("/users/{username}/roles", h.setUserRoles)
+		})
+
+		// Read routes
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopeRead))
+
+			r.Get("/profile", h.getMyProfile)
+			r.Get("/users/{username}/followers", h.getFollowers)
+			r.Get("/users/{username}/following", h.getFollowing)
+			r.Get("/feed", h.getFeed)
+			r.Get("/feed/for-you", h.getForYouFeed)
+			r.Get("/posts/{postID}/comments", h.getComments)
+			r.Get("/posts/{postID}/comments/{commentID}/thread", h.getCommentThread)
+			r.Get("/posts/{postID}/comments/{commentID}/revisions", h.getCommentRevisions)
+		})
+
+		// Profile routes
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopeProfileWrite))
+
+			r.Put("/profile", h.updateProfile)
+		})
 
 		// Following routes
-		r.Post("/users/{username}/follow", h.followUser)
-		r.Delete("/users/{username}/follow", h.unfollowUser)
-		r.Get("/users/{username}/followers", h.getFollowers)
-		r.Get("/users/{username}/following", h.getFollowing)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopeFollowsWrite))
+
+			r.Post("/users/{username}/follow", h.followUser)
+			r.Delete("/users/{username}/follow", h.unfollowUser)
+		})
 
 		// Post routes
-		r.Post("/posts", h.createPost)
-		r.Put("/posts/{postID}", h.updatePost)
-		r.Delete("/posts/{postID}", h.deletePost)
-		r.Get("/feed", h.getFeed)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopePostsWrite))
 
-		// Like routes
-		r.Post("/posts/{postID}/like", h.likePost)
-		r.Delete("/posts/{postID}/like", h.unlikePost)
+			r.Post("/posts", h.createPost)
+			r.Put("/posts/{postID}", h.updatePost)
+			r.Delete("/posts/{postID}", h.deletePost)
+			r.Post("/posts/{postID}/repost", h.repostPost)
+			r.Delete("/posts/{postID}/repost", h.unrepostPost)
+		})
+
+		// Like and reaction routes. Likes are 👍 reactions.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopeLikesWrite))
+
+			r.Post("/posts/{postID}/like", h.likePost)
+			r.Delete("/posts/{postID}/like", h.unlikePost)
+			r.Put("/posts/{postID}/reactions/{emoji}", h.addReaction)
+			r.Delete("/posts/{postID}/reactions/{emoji}", h.removeReaction)
+			r.Put("/posts/{postID}/comments/{commentID}/reactions/{emoji}", h.addReaction)
+			r.Delete("/posts/{postID}/comments/{commentID}/reactions/{emoji}", h.removeReaction)
+		})
 
 		// Comment routes
-		r.Post("/posts/{postID}/comments", h.addComment)
-		r.Get("/posts/{postID}/comments", h.getComments)
-		r.Delete("/posts/{postID}/comments/{commentID}", h.deleteComment)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(entity.ScopeCommentsWrite))
+
+			r.Post("/posts/{postID}/comments", h.addComment)
+			r.Put("/posts/{postID}/comments/{commentID}", h.updateComment)
+			r.Delete("/posts/{postID}/comments/{commentID}", h.deleteComment)
+		})
 	})
-}
\ No newline at end of file
+}
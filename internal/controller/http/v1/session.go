@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+)
+
+type Session struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Current    bool   `json:"current"`
+}
+
+type sessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+func (h *Handler) getSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	currentID, _ := r.Context().Value(middleware.SessionContextKey).(uuid.UUID)
+
+	sessions, err := h.sessionUseCase.ListSessions(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseSessions := make([]Session, len(sessions))
+	for i, session := range sessions {
+		responseSessions[i] = Session{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.String(),
+			LastUsedAt: session.LastUsedAt.String(),
+			ExpiresAt:  session.ExpiresAt.String(),
+			Current:    session.ID == currentID,
+		}
+	}
+
+	response := sessionsResponse{
+		Sessions: responseSessions,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) deleteSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	sessionIDStr := chi.URLParam(r, "sessionID")
+	if sessionIDStr == "" {
+		writeError(w, paramError("sessionID", "session ID is required"))
+		return
+	}
+
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		writeError(w, paramError("sessionID", "invalid session ID"))
+		return
+	}
+
+	err = h.sessionUseCase.RevokeSession(r.Context(), sessionID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
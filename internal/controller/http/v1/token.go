@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+	"social/api/internal/entity"
+)
+
+type createTokenRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Token struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type createTokenResponse struct {
+	// Token is the plaintext secret; it is only ever returned once.
+	Token string `json:"token"`
+	Info  Token  `json:"info"`
+}
+
+type tokensResponse struct {
+	Tokens []Token `json:"tokens"`
+}
+
+func (h *Handler) createToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	var req createTokenRequest
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	token, plaintext, err := h.tokenUseCase.CreateToken(r.Context(), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := createTokenResponse{
+		Token: plaintext,
+		Info:  newToken(token),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) getTokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	tokens, err := h.tokenUseCase.ListTokens(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responseTokens := make([]Token, len(tokens))
+	for i := range tokens {
+		responseTokens[i] = newToken(&tokens[i])
+	}
+
+	response := tokensResponse{
+		Tokens: responseTokens,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) deleteToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	tokenIDStr := chi.URLParam(r, "tokenID")
+	if tokenIDStr == "" {
+		writeError(w, paramError("tokenID", "token ID is required"))
+		return
+	}
+
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		writeError(w, paramError("tokenID", "invalid token ID"))
+		return
+	}
+
+	err = h.tokenUseCase.RevokeToken(r.Context(), tokenID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newToken(token *entity.Token) Token {
+	response := Token{
+		ID:        token.ID.String(),
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt.String(),
+	}
+	if token.LastUsedAt != nil {
+		lastUsedAt := token.LastUsedAt.String()
+		response.LastUsedAt = &lastUsedAt
+	}
+	if token.ExpiresAt != nil {
+		expiresAt := token.ExpiresAt.String()
+		response.ExpiresAt = &expiresAt
+	}
+	return response
+}
@@ -2,33 +2,50 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"social/api/internal/controller/http/middleware"
+	"social/api/internal/usecase"
 )
 
 type updateProfileRequest struct {
-	Name     *string `json:"name,omitempty"`
-	Bio      *string `json:"bio,omitempty"`
-	ImageURL *string `json:"image_url,omitempty"`
+	Name         *string `json:"name,omitempty"`
+	Bio          *string `json:"bio,omitempty"`
+	ImageURL     *string `json:"image_url,omitempty" validate:"omitempty,url"`
+	PrivateLikes *bool   `json:"private_likes,omitempty"`
 }
 
 type searchUsersResponse struct {
 	Users []User `json:"users"`
 }
 
+type usersPageResponse struct {
+	Users      []User  `json:"users"`
+	NextCursor *string `json:"next_cursor"`
+}
+
 func (h *Handler) getProfile(w http.ResponseWriter, r *http.Request) {
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
 		return
 	}
 
 	user, err := h.userUseCase.GetProfile(r.Context(), username)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		// Renamed accounts stay reachable under their old username
+		renamed, renamedErr := h.userUseCase.FindRenamedUser(r.Context(), username)
+		if renamedErr == nil {
+			http.Redirect(w, r, "/users/"+url.PathEscape(renamed.Username), http.StatusMovedPermanently)
+			return
+		}
+	}
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
@@ -50,25 +67,27 @@ func (h *Handler) getProfile(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getMyProfile(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
-	user, err := h.userUseCase.GetProfile(r.Context(), userID.String())
+	user, err := h.userUseCase.GetProfileByID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
 	response := User{
-		ID:        user.ID.String(),
-		Name:      user.Name,
-		Username:  user.Username,
-		Email:     user.Email,
-		Bio:       user.Bio,
-		ImageURL:  user.ImageURL,
-		CreatedAt: user.CreatedAt.String(),
-		UpdatedAt: user.UpdatedAt.String(),
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: emailVerified(user),
+		PrivateLikes:  &user.PrivateLikes,
+		Bio:           user.Bio,
+		ImageURL:      user.ImageURL,
+		CreatedAt:     user.CreatedAt.String(),
+		UpdatedAt:     user.UpdatedAt.String(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -78,31 +97,33 @@ func (h *Handler) getMyProfile(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) updateProfile(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	var req updateProfileRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	user, err := h.userUseCase.UpdateProfile(r.Context(), userID, req.Name, req.Bio, req.ImageURL)
+	user, err := h.userUseCase.UpdateProfile(r.Context(), userID, req.Name, req.Bio, req.ImageURL, req.PrivateLikes)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
 	response := User{
-		ID:        user.ID.String(),
-		Name:      user.Name,
-		Username:  user.Username,
-		Email:     user.Email,
-		Bio:       user.Bio,
-		ImageURL:  user.ImageURL,
-		CreatedAt: user.CreatedAt.String(),
-		UpdatedAt: user.UpdatedAt.String(),
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: emailVerified(user),
+		PrivateLikes:  &user.PrivateLikes,
+		Bio:           user.Bio,
+		ImageURL:      user.ImageURL,
+		CreatedAt:     user.CreatedAt.String(),
+		UpdatedAt:     user.UpdatedAt.String(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -112,13 +133,13 @@ func (h *Handler) updateProfile(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) searchUsers(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		http.Error(w, "query parameter 'q' is required", http.StatusBadRequest)
+		writeError(w, paramError("q", "query parameter 'q' is required"))
 		return
 	}
 
 	users, err := h.userUseCase.SearchUsers(r.Context(), query)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -148,21 +169,21 @@ func (h *Handler) followUser(w http.ResponseWriter, r *http.Request) {
 	// Get the authenticated user ID
 	followerID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	// Get the username to follow from the URL
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
 		return
 	}
 
 	// Get the user to follow
 	user, err := h.userUseCase.GetProfile(r.Context(), username)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
@@ -171,7 +192,7 @@ func (h *Handler) followUser(w http.ResponseWriter, r *http.Request) {
 	// Follow the user
 	err = h.interactionUseCase.FollowUser(r.Context(), userID, followerID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -182,21 +203,21 @@ func (h *Handler) unfollowUser(w http.ResponseWriter, r *http.Request) {
 	// Get the authenticated user ID
 	followerID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized)
 		return
 	}
 
 	// Get the username to unfollow from the URL
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
 		return
 	}
 
 	// Get the user to unfollow
 	user, err := h.userUseCase.GetProfile(r.Context(), username)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
@@ -205,7 +226,7 @@ func (h *Handler) unfollowUser(w http.ResponseWriter, r *http.Request) {
 	// Unfollow the user
 	err = h.interactionUseCase.UnfollowUser(r.Context(), userID, followerID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -215,13 +236,19 @@ func (h *Handler) unfollowUser(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getFollowers(w http.ResponseWriter, r *http.Request) {
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
 		return
 	}
 
-	followers, err := h.interactionUseCase.GetFollowers(r.Context(), username)
+	limit, cursor, err := pageParams(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
+		return
+	}
+
+	followers, next, err := h.interactionUseCase.GetFollowers(r.Context(), username, limit, cursor)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -239,8 +266,9 @@ func (h *Handler) getFollowers(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response := searchUsersResponse{
-		Users: responseUsers,
+	response := usersPageResponse{
+		Users:      responseUsers,
+		NextCursor: nextCursor(next),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -250,13 +278,19 @@ func (h *Handler) getFollowers(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getFollowing(w http.ResponseWriter, r *http.Request) {
 	username := chi.URLParam(r, "username")
 	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
+		writeError(w, paramError("username", "username is required"))
+		return
+	}
+
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	following, err := h.interactionUseCase.GetFollowing(r.Context(), username)
+	following, next, err := h.interactionUseCase.GetFollowing(r.Context(), username, limit, cursor)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
@@ -274,10 +308,44 @@ func (h *Handler) getFollowing(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response := searchUsersResponse{
-		Users: responseUsers,
+	response := usersPageResponse{
+		Users:      responseUsers,
+		NextCursor: nextCursor(next),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+func (h *Handler) getLikedPosts(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	if username == "" {
+		writeError(w, paramError("username", "username is required"))
+		return
+	}
+
+	limit, cursor, err := pageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	posts, next, err := h.interactionUseCase.GetLikedPosts(r.Context(), username, viewerID(r), limit, cursor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responsePosts := make([]Post, len(posts))
+	for i, post := range posts {
+		responsePosts[i] = newPost(&post)
+	}
+
+	response := postsResponse{
+		Posts:      responsePosts,
+		NextCursor: nextCursor(next),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"social/api/internal/controller/http/middleware"
+)
+
+func (h *Handler) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, paramError("token", "token is required"))
+		return
+	}
+
+	err := h.verificationUseCase.VerifyEmail(r.Context(), token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := messageResponse{
+		Message: "email address verified",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) resendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserContextKey).(uuid.UUID)
+	if !ok {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	err := h.verificationUseCase.ResendVerification(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
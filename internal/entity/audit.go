@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditLoginLocked = "login_locked"
+)
+
+type AuditEvent struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Event     string     `json:"event" db:"event"`
+	Email     string     `json:"email" db:"email"`
+	IPAddress string     `json:"ip_address" db:"ip_address"`
+	Details   string     `json:"details" db:"details"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type LoginAttempt struct {
+	Scope         string     `json:"scope" db:"scope"`
+	Key           string     `json:"key" db:"key"`
+	Failures      int        `json:"failures" db:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at" db:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+}
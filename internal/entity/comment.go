@@ -6,10 +6,40 @@ import (
 	"github.com/google/uuid"
 )
 
+// DeletedCommentContent replaces the content of a deleted comment that is
+// kept in its thread because it has replies.
+const DeletedCommentContent = "[deleted]"
+
 type Comment struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	PostID    uuid.UUID `json:"post_id" db:"post_id"`
-	AuthorID  uuid.UUID `json:"author_id" db:"author_id"`
-	Content   string    `json:"content" db:"content"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-}
\ No newline at end of file
+	ID       uuid.UUID `json:"id" db:"id"`
+	PostID   uuid.UUID `json:"post_id" db:"post_id"`
+	AuthorID uuid.UUID `json:"author_id" db:"author_id"`
+	// ParentCommentID is nil for top-level comments, which have depth 0
+	ParentCommentID *uuid.UUID `json:"parent_comment_id,omitempty" db:"parent_comment_id"`
+	Depth           int        `json:"depth" db:"depth"`
+	ReplyCount      int        `json:"reply_count" db:"reply_count"`
+	Content         string     `json:"content" db:"content"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsEdited reports whether the content was changed after the comment was
+// written. Comments are written with updated_at equal to created_at.
+func (c *Comment) IsEdited() bool {
+	return c.UpdatedAt.After(c.CreatedAt)
+}
+
+// CommentRevision is a prior version of an edited comment. CreatedAt is when
+// the version was written and ReplacedAt when an edit replaced it.
+type CommentRevision struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CommentID  uuid.UUID `json:"comment_id" db:"comment_id"`
+	Content    string    `json:"content" db:"content"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at" db:"replaced_at"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedItemType string
+
+const (
+	FeedItemPost   FeedItemType = "post"
+	FeedItemRepost FeedItemType = "repost"
+)
+
+// FeedItem is an entry of a home feed. Actor is whoever put the post there:
+// its author for posts, the reposting user for reposts. The Post of a repost
+// is the reposted post, or the repost itself once that post is deleted.
+type FeedItem struct {
+	Type  FeedItemType `json:"type"`
+	Actor User         `json:"actor"`
+	Post  Post         `json:"post"`
+	// ID and CreatedAt identify the entry itself, which for a repost is not
+	// the post but the repost. The feed is ordered and paged by them.
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RankedFeedItem is a feed item with its ranking score and the feature values
+// the score was computed from.
+type RankedFeedItem struct {
+	FeedItem
+	Score    float64            `json:"score"`
+	Features map[string]float64 `json:"features"`
+}
+
+// PostStats counts the interactions with a post.
+type PostStats struct {
+	Likes    int `json:"likes"`
+	Comments int `json:"comments"`
+}
+
+// PostEngagement is the weighted engagement a post got within the trending
+// window, with the time the post was created.
+type PostEngagement struct {
+	PostID     uuid.UUID
+	CreatedAt  time.Time
+	Engagement float64
+}
+
+// TrendingScore is the engagement velocity a post is ranked by in the
+// trending posts.
+type TrendingScore struct {
+	PostID uuid.UUID
+	Score  float64
+}
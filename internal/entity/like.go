@@ -10,4 +10,10 @@ type Like struct {
 	UserID    uuid.UUID `json:"user_id" db:"user_id"`
 	PostID    uuid.UUID `json:"post_id" db:"post_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
-}
\ No newline at end of file
+}
+
+// Liker is a user who liked a post.
+type Liker struct {
+	User    User      `json:"user"`
+	LikedAt time.Time `json:"liked_at"`
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a position in a list ordered by (created_at, id). Lists are
+// paged by key rather than offset, so rows added while a client scrolls
+// neither shift nor repeat the pages it has not fetched yet.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// PageRequest asks for up to Limit items after the After cursor, or from the
+// start of the list if After is nil.
+type PageRequest struct {
+	Limit int
+	After *Cursor
+}
+
+// Encode returns the opaque form handed to clients.
+func (c Cursor) Encode() string {
+	b := make([]byte, 8, 8+len(c.ID))
+	binary.BigEndian.PutUint64(b, uint64(c.CreatedAt.UnixMicro()))
+	b = append(b, c.ID[:]...)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// RankedCursor is a position in a ranked list: the offset into the ranking as
+// it stood at AsOf. Ranking again as of the same time gives the same order, so
+// the pages of a ranked list fit together without being stored anywhere.
+type RankedCursor struct {
+	AsOf   time.Time
+	Offset int
+}
+
+// Encode returns the opaque form handed to clients.
+func (c RankedCursor) Encode() string {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint64(b, uint64(c.AsOf.UnixMicro()))
+	binary.BigEndian.PutUint32(b[8:], uint32(c.Offset))
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func ParseRankedCursor(s string) (*RankedCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != 12 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &RankedCursor{
+		AsOf:   time.UnixMicro(int64(binary.BigEndian.Uint64(b[:8]))),
+		Offset: int(binary.BigEndian.Uint32(b[8:])),
+	}, nil
+}
+
+func ParseCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != 8+len(uuid.UUID{}) {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.FromBytes(b[8:])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{
+		CreatedAt: time.UnixMicro(int64(binary.BigEndian.Uint64(b[:8]))),
+		ID:        id,
+	}, nil
+}
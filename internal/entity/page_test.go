@@ -0,0 +1,116 @@
+package entity_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor entity.Cursor
+	}{
+		{"now", entity.Cursor{CreatedAt: time.Now().Truncate(time.Microsecond), ID: uuid.New()}},
+		{"epoch", entity.Cursor{CreatedAt: time.UnixMicro(0), ID: uuid.Nil}},
+		{"before epoch", entity.Cursor{CreatedAt: time.Date(1969, 7, 20, 20, 17, 40, 123456000, time.UTC), ID: uuid.Max}},
+		{"far future", entity.Cursor{CreatedAt: time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC), ID: uuid.New()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entity.ParseCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("ParseCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.ID != tt.cursor.ID {
+				t.Errorf("ParseCursor() = %v, want %v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestCursorDropsSubMicroseconds(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+
+	got, err := entity.ParseCursor(entity.Cursor{CreatedAt: at, ID: uuid.New()}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := at.Truncate(time.Microsecond); !got.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v like the database stores it", got.CreatedAt, want)
+	}
+}
+
+func TestParseCursorInvalid(t *testing.T) {
+	valid := entity.Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"padded", valid + "=="},
+		{"too short", valid[:len(valid)-2]},
+		{"too long", base64.RawURLEncoding.EncodeToString(make([]byte, 25))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.ParseCursor(tt.cursor)
+			if !errors.Is(err, entity.ErrInvalidCursor) {
+				t.Errorf("ParseCursor(%q) error = %v, want %v", tt.cursor, err, entity.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestRankedCursorRoundTrip(t *testing.T) {
+	asOf := time.Now().Truncate(time.Microsecond)
+
+	tests := []struct {
+		name   string
+		cursor entity.RankedCursor
+	}{
+		{"first page", entity.RankedCursor{AsOf: asOf}},
+		{"later page", entity.RankedCursor{AsOf: asOf, Offset: 40}},
+		{"large offset", entity.RankedCursor{AsOf: asOf, Offset: 1 << 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entity.ParseRankedCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("ParseRankedCursor() error = %v", err)
+			}
+			if !got.AsOf.Equal(tt.cursor.AsOf) || got.Offset != tt.cursor.Offset {
+				t.Errorf("ParseRankedCursor() = %v, want %v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParseRankedCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"keyset cursor", entity.Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.ParseRankedCursor(tt.cursor)
+			if !errors.Is(err, entity.ErrInvalidCursor) {
+				t.Errorf("ParseRankedCursor(%q) error = %v, want %v", tt.cursor, err, entity.ErrInvalidCursor)
+			}
+		})
+	}
+}
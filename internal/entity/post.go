@@ -7,10 +7,37 @@ import (
 )
 
 type Post struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	AuthorID  uuid.UUID `json:"author_id" db:"author_id"`
-	Content   string    `json:"content" db:"content"`
-	ImageURL  *string   `json:"image_url,omitempty" db:"image_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	ID       uuid.UUID `json:"id" db:"id"`
+	AuthorID uuid.UUID `json:"author_id" db:"author_id"`
+	Content  string    `json:"content" db:"content"`
+	ImageURL *string   `json:"image_url,omitempty" db:"image_url"`
+	// A repost has no content of its own; a quote post does. Either points at
+	// its original, which may have been deleted since.
+	RepostOfID   *uuid.UUID `json:"repost_of_id,omitempty" db:"repost_of_id"`
+	QuoteOfID    *uuid.UUID `json:"quote_of_id,omitempty" db:"quote_of_id"`
+	LikeCount    int        `json:"like_count" db:"like_count"`
+	CommentCount int        `json:"comment_count" db:"comment_count"`
+	RepostCount  int        `json:"repost_count" db:"repost_count"`
+	QuoteCount   int        `json:"quote_count" db:"quote_count"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Author, Original, Reactions and the viewer flags are not columns of
+	// posts; the use cases fill them in for the viewer.
+	Author    *User            `json:"author,omitempty" db:"-"`
+	Reactions *ReactionSummary `json:"reactions,omitempty" db:"-"`
+	LikedByMe bool             `json:"liked_by_me" db:"-"`
+	// Original is the reposted or quoted post. It is nil and OriginalDeleted
+	// is set when that post no longer exists.
+	Original        *Post `json:"original,omitempty" db:"-"`
+	OriginalDeleted bool  `json:"original_deleted,omitempty" db:"-"`
+	RepostedByMe    bool  `json:"reposted_by_me" db:"-"`
+}
+
+// OriginalID returns the post this one reposts or quotes, or nil.
+func (p *Post) OriginalID() *uuid.UUID {
+	if p.RepostOfID != nil {
+		return p.RepostOfID
+	}
+	return p.QuoteOfID
+}
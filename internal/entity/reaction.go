@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReactionTargetType string
+
+const (
+	ReactionTargetPost    ReactionTargetType = "post"
+	ReactionTargetComment ReactionTargetType = "comment"
+)
+
+// LikeEmoji is the reaction that likes are stored as.
+const LikeEmoji = "👍"
+
+type Reaction struct {
+	UserID     uuid.UUID          `json:"user_id" db:"user_id"`
+	TargetType ReactionTargetType `json:"target_type" db:"target_type"`
+	TargetID   uuid.UUID          `json:"target_id" db:"target_id"`
+	Emoji      string             `json:"emoji" db:"emoji"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+}
+
+// ReactionSummary counts the reactions to a post or comment per emoji and
+// lists the ones the viewer added.
+type ReactionSummary struct {
+	Counts map[string]int `json:"counts"`
+	Mine   []string       `json:"mine"`
+}
+
+// ReactionTarget addresses what a reaction is for: the post, or one of its
+// comments if CommentID is set.
+type ReactionTarget struct {
+	PostID    uuid.UUID
+	CommentID *uuid.UUID
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "slices"
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// Roles lists every role that can be assigned to a user.
+var Roles = []string{RoleAdmin, RoleModerator}
+
+// HasAnyRole reports whether roles contains at least one of want.
+func HasAnyRole(roles []string, want ...string) bool {
+	for _, role := range want {
+		if slices.Contains(roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
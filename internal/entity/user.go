@@ -7,13 +7,17 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password_hash"`
-	Bio       *string   `json:"bio,omitempty" db:"bio"`
-	ImageURL  *string   `json:"image_url,omitempty" db:"profile_picture_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	ID              uuid.UUID  `json:"id" db:"id"`
+	Name            string     `json:"name" db:"name"`
+	Username        string     `json:"username" db:"username"`
+	Email           string     `json:"email" db:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	Password        string     `json:"-" db:"password_hash"`
+	Roles           []string   `json:"roles,omitempty" db:"roles"`
+	Bio             *string    `json:"bio,omitempty" db:"bio"`
+	ImageURL        *string    `json:"image_url,omitempty" db:"profile_picture_url"`
+	// PrivateLikes hides which posts the user liked from everyone else
+	PrivateLikes bool      `json:"private_likes" db:"private_likes"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
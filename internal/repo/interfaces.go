@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"social/api/internal/entity"
@@ -10,37 +11,135 @@ import (
 type User interface {
 	Create(ctx context.Context, user *entity.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error)
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	UpdateUsername(ctx context.Context, id uuid.UUID, username string) error
+	GetByPreviousUsername(ctx context.Context, username string) (*entity.User, error)
+	LastUsernameChange(ctx context.Context, id uuid.UUID) (*time.Time, error)
+	SetRoles(ctx context.Context, id uuid.UUID, roles []string) error
 	Search(ctx context.Context, query string) ([]entity.User, error)
 }
 
 type Post interface {
 	Create(ctx context.Context, post *entity.Post) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error)
-	GetByAuthorID(ctx context.Context, authorID uuid.UUID) ([]entity.Post, error)
-	GetFeed(ctx context.Context, userID uuid.UUID) ([]entity.Post, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Post, error)
+	GetByAuthorID(ctx context.Context, authorID uuid.UUID, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error)
+	GetRecent(ctx context.Context, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error)
+	GetRepost(ctx context.Context, authorID, postID uuid.UUID) (*entity.Post, error)
+	GetRepostedIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
 	Update(ctx context.Context, post *entity.Post) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type Comment interface {
 	Create(ctx context.Context, comment *entity.Comment) error
-	GetByPostID(ctx context.Context, postID uuid.UUID) ([]entity.Comment, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Comment, error)
+	GetByPostID(ctx context.Context, postID uuid.UUID, page entity.PageRequest) ([]entity.Comment, *entity.Cursor, error)
+	GetThread(ctx context.Context, postID, id uuid.UUID, page entity.PageRequest) ([]entity.Comment, *entity.Cursor, error)
+	Update(ctx context.Context, comment *entity.Comment) error
+	GetRevisions(ctx context.Context, id uuid.UUID) ([]entity.CommentRevision, error)
+	Delete(ctx context.Context, postID, id uuid.UUID) error
 }
 
 type Like interface {
-	Create(ctx context.Context, like *entity.Like) error
-	Delete(ctx context.Context, userID, postID uuid.UUID) error
-	Exists(ctx context.Context, userID, postID uuid.UUID) (bool, error)
+	GetLikers(ctx context.Context, postID, viewerID uuid.UUID, page entity.PageRequest) ([]entity.Liker, *entity.Cursor, error)
+	GetLikedPosts(ctx context.Context, userID uuid.UUID, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error)
+}
+
+type Reaction interface {
+	Create(ctx context.Context, reaction *entity.Reaction) error
+	Delete(ctx context.Context, userID uuid.UUID, targetType entity.ReactionTargetType, targetID uuid.UUID, emoji string) error
+	GetCounts(ctx context.Context, targetType entity.ReactionTargetType, targetIDs []uuid.UUID) (map[uuid.UUID]map[string]int, error)
+	GetUserReactions(ctx context.Context, userID uuid.UUID, targetType entity.ReactionTargetType, targetIDs []uuid.UUID) (map[uuid.UUID][]string, error)
 }
 
 type Follow interface {
 	Create(ctx context.Context, follow *entity.Follow) error
 	Delete(ctx context.Context, userID, followerID uuid.UUID) error
 	Exists(ctx context.Context, userID, followerID uuid.UUID) (bool, error)
-	GetFollowers(ctx context.Context, userID uuid.UUID) ([]entity.User, error)
-	GetFollowing(ctx context.Context, followerID uuid.UUID) ([]entity.User, error)
-}
\ No newline at end of file
+	GetFollowers(ctx context.Context, userID uuid.UUID, page entity.PageRequest) ([]entity.User, *entity.Cursor, error)
+	GetFollowing(ctx context.Context, followerID uuid.UUID, page entity.PageRequest) ([]entity.User, *entity.Cursor, error)
+}
+
+type Timeline interface {
+	FanOut(ctx context.Context, post *entity.Post, maxFollowers int) error
+	Backfill(ctx context.Context, userID, authorID uuid.UUID, limit int) error
+	RemoveAuthor(ctx context.Context, userID, authorID uuid.UUID) error
+	IsBuilt(ctx context.Context, userID uuid.UUID) (bool, error)
+	Rebuild(ctx context.Context, userID uuid.UUID, limit, maxFollowers int) error
+	Get(ctx context.Context, userID uuid.UUID, page entity.PageRequest, maxFollowers int) ([]entity.FeedItem, *entity.Cursor, error)
+}
+
+type Trending interface {
+	// GetEngagement sums the weighted reactions, comments, reposts and
+	// quotes of every post that got any since the given time.
+	GetEngagement(ctx context.Context, since time.Time) ([]entity.PostEngagement, error)
+	// Replace stores the given scores as the trending posts.
+	Replace(ctx context.Context, scores []entity.TrendingScore) error
+	Get(ctx context.Context, limit int) ([]entity.Post, error)
+}
+
+type FeedStats interface {
+	// GetPostStats counts the likes and comments of each post as of the
+	// given time.
+	GetPostStats(ctx context.Context, postIDs []uuid.UUID, asOf time.Time) (map[uuid.UUID]entity.PostStats, error)
+	// GetAffinity counts the viewer's reactions and comments on posts of
+	// each author between since and asOf.
+	GetAffinity(ctx context.Context, viewerID uuid.UUID, authorIDs []uuid.UUID, since, asOf time.Time) (map[uuid.UUID]int, error)
+}
+
+type Session interface {
+	Create(ctx context.Context, session *entity.Session, tokenHash string) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Session, error)
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Session, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string) (bool, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAllByUserID(ctx context.Context, userID, exceptID uuid.UUID) error
+}
+
+type Token interface {
+	Create(ctx context.Context, token *entity.Token, tokenHash string) error
+	GetByHash(ctx context.Context, tokenHash string) (*entity.Token, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Token, error)
+	Revoke(ctx context.Context, id, userID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type MFA interface {
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTP, error)
+	SaveTOTP(ctx context.Context, totp *entity.TOTP) error
+	EnableTOTP(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
+
+type LoginAttempt interface {
+	Get(ctx context.Context, scope, key string) (*entity.LoginAttempt, error)
+	RecordFailure(ctx context.Context, scope, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, scope, key string, until time.Time) error
+	Reset(ctx context.Context, scope, key string) error
+}
+
+type Audit interface {
+	Create(ctx context.Context, event *entity.AuditEvent) error
+}
+
+type OneTimeToken interface {
+	Create(ctx context.Context, token *entity.OneTimeToken, tokenHash string) error
+	Consume(ctx context.Context, tokenHash, purpose string) (*entity.OneTimeToken, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+type Identity interface {
+	Create(ctx context.Context, identity *entity.Identity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error)
+	TouchLastLogin(ctx context.Context, id uuid.UUID) error
+}
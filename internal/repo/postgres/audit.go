@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type AuditRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepo(db *pgxpool.Pool) repo.Audit {
+	return &AuditRepo{db: db}
+}
+
+func (r *AuditRepo) Create(ctx context.Context, event *entity.AuditEvent) error {
+	query := `INSERT INTO auth_audit_events (user_id, event, email, ip_address, details) 
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, event.UserID, event.Event, event.Email, event.IPAddress, event.Details).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", translateError(err))
+	}
+	return nil
+}
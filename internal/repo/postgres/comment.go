@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,48 +19,269 @@ func NewCommentRepo(db *pgxpool.Pool) repo.Comment {
 	return &CommentRepo{db: db}
 }
 
+// Create adds the comment and keeps the post's comment_count and, for
+// replies, the parent's reply_count in step.
 func (r *CommentRepo) Create(ctx context.Context, comment *entity.Comment) error {
-	query := `INSERT INTO comments (post_id, author_id, content) 
-	          VALUES ($1, $2, $3) RETURNING id, created_at`
-	err := r.db.QueryRow(ctx, query, comment.PostID, comment.AuthorID, comment.Content).Scan(&comment.ID, &comment.CreatedAt)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create comment: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO comments (post_id, author_id, content, parent_comment_id, depth, path) 
+	          VALUES ($1, $2, $3, $4, $5, '') RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, comment.PostID, comment.AuthorID, comment.Content, comment.ParentCommentID, comment.Depth).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", translateError(err))
+	}
+
+	// The path ends in the comment's own creation time and ID, so it can only
+	// be set once those are known
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET path = COALESCE((SELECT path || '/' FROM comments WHERE id = $2), '') || to_char(created_at AT TIME ZONE 'UTC', 'YYYYMMDDHH24MISSUS') || id::text
+		WHERE id = $1`, comment.ID, comment.ParentCommentID)
+	if err != nil {
+		return fmt.Errorf("failed to set comment path: %w", translateError(err))
+	}
+
+	if comment.ParentCommentID != nil {
+		_, err = tx.Exec(ctx, `UPDATE comments SET reply_count = reply_count + 1 WHERE id = $1`, *comment.ParentCommentID)
+		if err != nil {
+			return fmt.Errorf("failed to update reply count: %w", translateError(err))
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE posts SET comment_count = comment_count + 1 WHERE id = $1`, comment.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment count: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 	return nil
 }
 
-func (r *CommentRepo) GetByPostID(ctx context.Context, postID uuid.UUID) ([]entity.Comment, error) {
+// GetByID also returns deleted comments.
+func (r *CommentRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Comment, error) {
+	var comment entity.Comment
+	query := `SELECT id, post_id, author_id, parent_comment_id, depth, reply_count, content, created_at, updated_at, deleted_at FROM comments WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(commentFields(&comment)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment by ID: %w", translateError(err))
+	}
+	return &comment, nil
+}
+
+// GetByPostID pages through all of a post's comments as one thread, depth
+// first with the oldest replies first. Deleted comments are only returned
+// while they have replies.
+func (r *CommentRepo) GetByPostID(ctx context.Context, postID uuid.UUID, page entity.PageRequest) ([]entity.Comment, *entity.Cursor, error) {
+	// Comments are never removed from a post, so the cursor's comment can
+	// always be found to continue after its path
+	_, afterID := keyset(page)
 	rows, err := r.db.Query(ctx, `
-		SELECT id, post_id, author_id, content, created_at 
+		SELECT id, post_id, author_id, parent_comment_id, depth, reply_count, content, created_at, updated_at, deleted_at 
 		FROM comments 
-		WHERE post_id = $1 
-		ORDER BY created_at ASC`, postID)
+		WHERE post_id = $1
+		  AND (deleted_at IS NULL OR reply_count > 0)
+		  AND ($2::uuid IS NULL OR path > (SELECT path FROM comments WHERE id = $2))
+		ORDER BY path
+		LIMIT $3`, postID, afterID, page.Limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comments by post ID: %w", err)
+		return nil, nil, fmt.Errorf("failed to get comments by post ID: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var comments []entity.Comment
 	for rows.Next() {
 		var comment entity.Comment
-		err := rows.Scan(&comment.ID, &comment.PostID, &comment.AuthorID, &comment.Content, &comment.CreatedAt)
+		err := rows.Scan(commentFields(&comment)...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan comment: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan comment: %w", translateError(err))
 		}
 		comments = append(comments, comment)
 	}
 
-	return comments, nil
+	return nextComments(comments, page)
+}
+
+// GetThread pages through the comment and its replies the same way
+// GetByPostID pages through a post's comments.
+func (r *CommentRepo) GetThread(ctx context.Context, postID, id uuid.UUID, page entity.PageRequest) ([]entity.Comment, *entity.Cursor, error) {
+	_, afterID := keyset(page)
+	rows, err := r.db.Query(ctx, `
+		SELECT c.id, c.post_id, c.author_id, c.parent_comment_id, c.depth, c.reply_count, c.content, c.created_at, c.updated_at, c.deleted_at
+		FROM comments c
+		JOIN comments root ON root.id = $2
+		WHERE c.post_id = $1
+		  AND (c.path = root.path OR c.path LIKE root.path || '/%')
+		  AND (c.deleted_at IS NULL OR c.reply_count > 0)
+		  AND ($3::uuid IS NULL OR c.path > (SELECT path FROM comments WHERE id = $3))
+		ORDER BY c.path
+		LIMIT $4`, postID, id, afterID, page.Limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get comment thread: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var comments []entity.Comment
+	for rows.Next() {
+		var comment entity.Comment
+		err := rows.Scan(commentFields(&comment)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan comment: %w", translateError(err))
+		}
+		comments = append(comments, comment)
+	}
+
+	return nextComments(comments, page)
+}
+
+// Update replaces the content of the comment and keeps the version it
+// replaces as a revision.
+func (r *CommentRepo) Update(ctx context.Context, comment *entity.Comment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the row so that concurrent edits each save the version they replace
+	var content string
+	var updatedAt time.Time
+	query := `SELECT content, updated_at FROM comments WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(ctx, query, comment.ID).Scan(&content, &updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO comment_revisions (comment_id, content, created_at) VALUES ($1, $2, $3)`, comment.ID, content, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save comment revision: %w", translateError(err))
+	}
+
+	query = `UPDATE comments SET content = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at`
+	err = tx.QueryRow(ctx, query, comment.Content, comment.ID).Scan(&comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+// GetRevisions returns the prior versions of the comment, oldest first.
+func (r *CommentRepo) GetRevisions(ctx context.Context, id uuid.UUID) ([]entity.CommentRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, comment_id, content, created_at, replaced_at
+		FROM comment_revisions
+		WHERE comment_id = $1
+		ORDER BY created_at, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment revisions: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	revisions := []entity.CommentRevision{}
+	for rows.Next() {
+		var revision entity.CommentRevision
+		err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Content, &revision.CreatedAt, &revision.ReplacedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment revision: %w", translateError(err))
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
 }
 
-func (r *CommentRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM comments WHERE id = $1`
-	result, err := r.db.Exec(ctx, query, id)
+// Delete only deletes the comment if it belongs to postID. Its reactions are
+// removed and its content is replaced, but kept as a last revision for
+// moderators. The post's comment_count is kept in step, and the comment
+// itself stays as a placeholder while it has replies. Without replies
+// it is hidden and no longer counts as a reply, which can leave a deleted
+// parent without replies to hide in turn, and so on up the thread.
+func (r *CommentRepo) Delete(ctx context.Context, postID, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	var content string
+	var updatedAt time.Time
+	query := `SELECT content, updated_at FROM comments
+	          WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(ctx, query, id, postID).Scan(&content, &updatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to delete comment: %w", err)
+		return fmt.Errorf("failed to get comment: %w", translateError(err))
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("comment not found")
+
+	_, err = tx.Exec(ctx, `INSERT INTO comment_revisions (comment_id, content, created_at) VALUES ($1, $2, $3)`, id, content, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save comment revision: %w", translateError(err))
+	}
+
+	var parentID *uuid.UUID
+	var replies int
+	query = `UPDATE comments SET content = $2, deleted_at = NOW()
+	         WHERE id = $1 RETURNING parent_comment_id, reply_count`
+	err = tx.QueryRow(ctx, query, id, entity.DeletedCommentContent).Scan(&parentID, &replies)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM reactions WHERE target_type = 'comment' AND target_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reactions: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE posts SET comment_count = comment_count - 1 WHERE id = $1`, postID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment count: %w", translateError(err))
+	}
+
+	for replies == 0 && parentID != nil {
+		var deleted bool
+		err = tx.QueryRow(ctx, `
+			UPDATE comments SET reply_count = reply_count - 1 WHERE id = $1
+			RETURNING parent_comment_id, reply_count, deleted_at IS NOT NULL`, *parentID).Scan(&parentID, &replies, &deleted)
+		if err != nil {
+			return fmt.Errorf("failed to update reply count: %w", translateError(err))
+		}
+		if !deleted {
+			break
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// commentFields lists the destinations of the comment columns in the order
+// the queries select them.
+func commentFields(comment *entity.Comment) []any {
+	return []any{
+		&comment.ID, &comment.PostID, &comment.AuthorID, &comment.ParentCommentID, &comment.Depth,
+		&comment.ReplyCount, &comment.Content, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+	}
+}
+
+func nextComments(comments []entity.Comment, page entity.PageRequest) ([]entity.Comment, *entity.Cursor, error) {
+	if len(comments) <= page.Limit {
+		return comments, nil, nil
+	}
+
+	comments = comments[:page.Limit]
+	last := comments[len(comments)-1]
+	return comments, &entity.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
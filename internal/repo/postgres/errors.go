@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"social/api/internal/usecase"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// uniqueErrors names the unique constraints that map to a specific use case
+// error; any other violation is reported as a plain usecase.ErrConflict.
+var uniqueErrors = map[string]error{
+	"users_email_key":    usecase.ErrEmailTaken,
+	"users_username_key": usecase.ErrUsernameTaken,
+}
+
+// translateError makes missing rows, references to missing rows and unique
+// violations recognizable to the use cases while keeping the driver error in
+// the chain for logging.
+func translateError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", usecase.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if known, ok := uniqueErrors[pgErr.ConstraintName]; ok {
+			return fmt.Errorf("%w: %w", known, err)
+		}
+		return fmt.Errorf("%w: %w", usecase.ErrConflict, err)
+	}
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w: %w", usecase.ErrNotFound, err)
+	}
+
+	return err
+}
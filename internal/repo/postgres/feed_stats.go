@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type FeedStatsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewFeedStatsRepo(db *pgxpool.Pool) repo.FeedStats {
+	return &FeedStatsRepo{db: db}
+}
+
+// GetPostStats counts the rows rather than reading like_count and
+// comment_count, so that a ranking as of some time can be repeated.
+func (r *FeedStatsRepo) GetPostStats(ctx context.Context, postIDs []uuid.UUID, asOf time.Time) (map[uuid.UUID]entity.PostStats, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.id,
+		       (SELECT COUNT(*) FROM reactions r
+		        WHERE r.target_type = 'post' AND r.target_id = p.id AND r.emoji = $3 AND r.created_at <= $2),
+		       (SELECT COUNT(*) FROM comments c
+		        WHERE c.post_id = p.id AND c.created_at <= $2 AND (c.deleted_at IS NULL OR c.deleted_at > $2))
+		FROM posts p
+		WHERE p.id = ANY($1)`, postIDs, asOf, entity.LikeEmoji)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post stats: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	stats := make(map[uuid.UUID]entity.PostStats, len(postIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var s entity.PostStats
+		err := rows.Scan(&id, &s.Likes, &s.Comments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post stats: %w", translateError(err))
+		}
+		stats[id] = s
+	}
+
+	return stats, nil
+}
+
+func (r *FeedStatsRepo) GetAffinity(ctx context.Context, viewerID uuid.UUID, authorIDs []uuid.UUID, since, asOf time.Time) (map[uuid.UUID]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT author_id, COUNT(*)
+		FROM (
+			SELECT p.author_id
+			FROM reactions r
+			JOIN posts p ON p.id = r.target_id AND r.target_type = 'post'
+			WHERE r.user_id = $1 AND r.created_at BETWEEN $3 AND $4 AND p.author_id = ANY($2)
+			UNION ALL
+			SELECT p.author_id
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id
+			WHERE c.author_id = $1 AND c.created_at BETWEEN $3 AND $4 AND (c.deleted_at IS NULL OR c.deleted_at > $4) AND p.author_id = ANY($2)
+		) interactions
+		GROUP BY author_id`, viewerID, authorIDs, since, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affinity: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	affinity := make(map[uuid.UUID]int, len(authorIDs))
+	for rows.Next() {
+		var authorID uuid.UUID
+		var count int
+		err := rows.Scan(&authorID, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan affinity: %w", translateError(err))
+		}
+		affinity[authorID] = count
+	}
+
+	return affinity, nil
+}
@@ -2,12 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
+	"social/api/internal/usecase"
 )
 
 type FollowRepo struct {
@@ -18,23 +22,60 @@ func NewFollowRepo(db *pgxpool.Pool) repo.Follow {
 	return &FollowRepo{db: db}
 }
 
+// Create adds the follow and keeps the followed user's follower_count in step.
 func (r *FollowRepo) Create(ctx context.Context, follow *entity.Follow) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
 	query := `INSERT INTO followers (user_id, follower_id) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING created_at`
-	err := r.db.QueryRow(ctx, query, follow.UserID, follow.FollowerID).Scan(&follow.CreatedAt)
+	err = tx.QueryRow(ctx, query, follow.UserID, follow.FollowerID).Scan(&follow.CreatedAt)
+	// ON CONFLICT DO NOTHING returns no row when the follow already exists
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("follow %w", usecase.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create follow: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET follower_count = follower_count + 1 WHERE id = $1`, follow.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update follower count: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create follow: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 	return nil
 }
 
 func (r *FollowRepo) Delete(ctx context.Context, userID, followerID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
 	query := `DELETE FROM followers WHERE user_id = $1 AND follower_id = $2`
-	result, err := r.db.Exec(ctx, query, userID, followerID)
+	result, err := tx.Exec(ctx, query, userID, followerID)
 	if err != nil {
-		return fmt.Errorf("failed to delete follow: %w", err)
+		return fmt.Errorf("failed to delete follow: %w", translateError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("follow relationship not found")
+		return fmt.Errorf("follow relationship %w", usecase.ErrNotFound)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET follower_count = follower_count - 1 WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update follower count: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 	return nil
 }
@@ -44,61 +85,85 @@ func (r *FollowRepo) Exists(ctx context.Context, userID, followerID uuid.UUID) (
 	query := `SELECT EXISTS(SELECT 1 FROM followers WHERE user_id = $1 AND follower_id = $2)`
 	err := r.db.QueryRow(ctx, query, userID, followerID).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("failed to check if follow exists: %w", err)
+		return false, fmt.Errorf("failed to check if follow exists: %w", translateError(err))
 	}
 	return exists, nil
 }
 
-func (r *FollowRepo) GetFollowers(ctx context.Context, userID uuid.UUID) ([]entity.User, error) {
+// GetFollowers pages through userID's followers, most recent follow first.
+func (r *FollowRepo) GetFollowers(ctx context.Context, userID uuid.UUID, page entity.PageRequest) ([]entity.User, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
 	rows, err := r.db.Query(ctx, `
-		SELECT u.id, u.name, u.username, u.email, u.password_hash, u.bio, u.profile_picture_url, u.created_at, u.updated_at
+		SELECT u.id, u.name, u.username, u.email, u.password_hash, u.bio, u.profile_picture_url, u.created_at, u.updated_at, f.created_at
 		FROM users u
 		JOIN followers f ON u.id = f.follower_id
 		WHERE f.user_id = $1
-		ORDER BY f.created_at DESC`, userID)
+		  AND ($2::timestamptz IS NULL OR (f.created_at, u.id) < ($2, $3::uuid))
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT $4`, userID, afterTime, afterID, page.Limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get followers: %w", err)
+		return nil, nil, fmt.Errorf("failed to get followers: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var users []entity.User
+	var followedAt []time.Time
 	for rows.Next() {
 		var user entity.User
+		var at time.Time
 		err := rows.Scan(
 			&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-			&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+			&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt, &at)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan user: %w", translateError(err))
 		}
 		users = append(users, user)
+		followedAt = append(followedAt, at)
+	}
+
+	if len(users) <= page.Limit {
+		return users, nil, nil
 	}
 
-	return users, nil
+	users = users[:page.Limit]
+	return users, &entity.Cursor{CreatedAt: followedAt[page.Limit-1], ID: users[page.Limit-1].ID}, nil
 }
 
-func (r *FollowRepo) GetFollowing(ctx context.Context, followerID uuid.UUID) ([]entity.User, error) {
+// GetFollowing pages through the users followerID follows, most recent first.
+func (r *FollowRepo) GetFollowing(ctx context.Context, followerID uuid.UUID, page entity.PageRequest) ([]entity.User, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
 	rows, err := r.db.Query(ctx, `
-		SELECT u.id, u.name, u.username, u.email, u.password_hash, u.bio, u.profile_picture_url, u.created_at, u.updated_at
+		SELECT u.id, u.name, u.username, u.email, u.password_hash, u.bio, u.profile_picture_url, u.created_at, u.updated_at, f.created_at
 		FROM users u
 		JOIN followers f ON u.id = f.user_id
 		WHERE f.follower_id = $1
-		ORDER BY f.created_at DESC`, followerID)
+		  AND ($2::timestamptz IS NULL OR (f.created_at, u.id) < ($2, $3::uuid))
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT $4`, followerID, afterTime, afterID, page.Limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get following: %w", err)
+		return nil, nil, fmt.Errorf("failed to get following: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var users []entity.User
+	var followedAt []time.Time
 	for rows.Next() {
 		var user entity.User
+		var at time.Time
 		err := rows.Scan(
 			&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-			&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+			&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt, &at)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan user: %w", translateError(err))
 		}
 		users = append(users, user)
+		followedAt = append(followedAt, at)
 	}
 
-	return users, nil
-}
\ No newline at end of file
+	if len(users) <= page.Limit {
+		return users, nil, nil
+	}
+
+	users = users[:page.Limit]
+	return users, &entity.Cursor{CreatedAt: followedAt[page.Limit-1], ID: users[page.Limit-1].ID}, nil
+}
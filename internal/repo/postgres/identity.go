@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type IdentityRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdentityRepo(db *pgxpool.Pool) repo.Identity {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) Create(ctx context.Context, identity *entity.Identity) error {
+	query := `INSERT INTO user_identities (user_id, provider, subject, email)
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at, last_login_at`
+	err := r.db.QueryRow(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email).Scan(
+		&identity.ID, &identity.CreatedAt, &identity.LastLoginAt)
+	if err != nil {
+		return fmt.Errorf("failed to create identity: %w", translateError(err))
+	}
+	return nil
+}
+
+// GetByProviderSubject returns nil without an error for unknown identities.
+func (r *IdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error) {
+	var identity entity.Identity
+	query := `SELECT id, user_id, provider, subject, email, created_at, last_login_at
+	          FROM user_identities WHERE provider = $1 AND subject = $2`
+	err := r.db.QueryRow(ctx, query, provider, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+		&identity.Email, &identity.CreatedAt, &identity.LastLoginAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity: %w", translateError(err))
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepo) TouchLastLogin(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE user_identities SET last_login_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update identity: %w", translateError(err))
+	}
+	return nil
+}
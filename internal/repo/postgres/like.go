@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,6 +11,8 @@ import (
 	"social/api/internal/repo"
 )
 
+// LikeRepo lists likes, which are stored as 👍 reactions to posts. They are
+// added and removed through ReactionRepo.
 type LikeRepo struct {
 	db *pgxpool.Pool
 }
@@ -18,33 +21,76 @@ func NewLikeRepo(db *pgxpool.Pool) repo.Like {
 	return &LikeRepo{db: db}
 }
 
-func (r *LikeRepo) Create(ctx context.Context, like *entity.Like) error {
-	query := `INSERT INTO likes (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING created_at`
-	err := r.db.QueryRow(ctx, query, like.UserID, like.PostID).Scan(&like.CreatedAt)
+// GetLikers pages through the users who liked a post, most recent like first.
+// Users with private likes are left out unless they are the viewer.
+func (r *LikeRepo) GetLikers(ctx context.Context, postID, viewerID uuid.UUID, page entity.PageRequest) ([]entity.Liker, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
+	rows, err := r.db.Query(ctx, `
+		SELECT u.id, u.name, u.username, u.profile_picture_url, l.created_at
+		FROM reactions l
+		JOIN users u ON u.id = l.user_id
+		WHERE l.target_type = $1 AND l.target_id = $2 AND l.emoji = $3
+		  AND (NOT u.private_likes OR u.id = $4)
+		  AND ($5::timestamptz IS NULL OR (l.created_at, l.user_id) < ($5, $6::uuid))
+		ORDER BY l.created_at DESC, l.user_id DESC
+		LIMIT $7`, entity.ReactionTargetPost, postID, entity.LikeEmoji, viewerID, afterTime, afterID, page.Limit+1)
 	if err != nil {
-		return fmt.Errorf("failed to create like: %w", err)
+		return nil, nil, fmt.Errorf("failed to get likers: %w", translateError(err))
 	}
-	return nil
-}
+	defer rows.Close()
 
-func (r *LikeRepo) Delete(ctx context.Context, userID, postID uuid.UUID) error {
-	query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2`
-	result, err := r.db.Exec(ctx, query, userID, postID)
-	if err != nil {
-		return fmt.Errorf("failed to delete like: %w", err)
+	var likers []entity.Liker
+	for rows.Next() {
+		var liker entity.Liker
+		err := rows.Scan(&liker.User.ID, &liker.User.Name, &liker.User.Username, &liker.User.ImageURL, &liker.LikedAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan liker: %w", translateError(err))
+		}
+		likers = append(likers, liker)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("like not found")
+
+	if len(likers) <= page.Limit {
+		return likers, nil, nil
 	}
-	return nil
+
+	likers = likers[:page.Limit]
+	last := likers[len(likers)-1]
+	return likers, &entity.Cursor{CreatedAt: last.LikedAt, ID: last.User.ID}, nil
 }
 
-func (r *LikeRepo) Exists(ctx context.Context, userID, postID uuid.UUID) (bool, error) {
-	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM likes WHERE user_id = $1 AND post_id = $2)`
-	err := r.db.QueryRow(ctx, query, userID, postID).Scan(&exists)
+// GetLikedPosts pages through the posts a user liked, most recent like first.
+func (r *LikeRepo) GetLikedPosts(ctx context.Context, userID uuid.UUID, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
+	rows, err := r.db.Query(ctx, `
+		SELECT p.id, p.author_id, p.content, p.image_url, p.repost_of_id, p.quote_of_id, p.like_count, p.comment_count, p.repost_count, p.quote_count, p.created_at, p.updated_at, l.created_at
+		FROM reactions l
+		JOIN posts p ON p.id = l.target_id
+		WHERE l.user_id = $1 AND l.target_type = $2 AND l.emoji = $3
+		  AND ($4::timestamptz IS NULL OR (l.created_at, l.target_id) < ($4, $5::uuid))
+		ORDER BY l.created_at DESC, l.target_id DESC
+		LIMIT $6`, userID, entity.ReactionTargetPost, entity.LikeEmoji, afterTime, afterID, page.Limit+1)
 	if err != nil {
-		return false, fmt.Errorf("failed to check if like exists: %w", err)
+		return nil, nil, fmt.Errorf("failed to get liked posts: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var posts []entity.Post
+	var likedAt []time.Time
+	for rows.Next() {
+		var post entity.Post
+		var at time.Time
+		err := rows.Scan(append(postFields(&post), &at)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan post: %w", translateError(err))
+		}
+		posts = append(posts, post)
+		likedAt = append(likedAt, at)
+	}
+
+	if len(posts) <= page.Limit {
+		return posts, nil, nil
 	}
-	return exists, nil
-}
\ No newline at end of file
+
+	posts = posts[:page.Limit]
+	return posts, &entity.Cursor{CreatedAt: likedAt[page.Limit-1], ID: posts[page.Limit-1].ID}, nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type LoginAttemptRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptRepo(db *pgxpool.Pool) repo.LoginAttempt {
+	return &LoginAttemptRepo{db: db}
+}
+
+// Get returns nil without an error when no failures were recorded for key.
+func (r *LoginAttemptRepo) Get(ctx context.Context, scope, key string) (*entity.LoginAttempt, error) {
+	var attempt entity.LoginAttempt
+	query := `SELECT scope, key, failures, last_failure_at, locked_until FROM login_attempts WHERE scope = $1 AND key = $2`
+	err := r.db.QueryRow(ctx, query, scope, key).Scan(
+		&attempt.Scope, &attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.LockedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempts: %w", translateError(err))
+	}
+	return &attempt, nil
+}
+
+// RecordFailure counts a failed attempt and returns the number of failures in
+// the current window. Failures older than window start a fresh count.
+func (r *LoginAttemptRepo) RecordFailure(ctx context.Context, scope, key string, window time.Duration) (int, error) {
+	var failures int
+	query := `INSERT INTO login_attempts (scope, key, failures) VALUES ($1, $2, 1)
+	          ON CONFLICT (scope, key) DO UPDATE SET 
+	              failures = CASE WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $3) 
+	                              THEN 1 ELSE login_attempts.failures + 1 END,
+	              last_failure_at = NOW()
+	          RETURNING failures`
+	err := r.db.QueryRow(ctx, query, scope, key, window.Seconds()).Scan(&failures)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", translateError(err))
+	}
+	return failures, nil
+}
+
+func (r *LoginAttemptRepo) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = $3 WHERE scope = $1 AND key = $2`
+	_, err := r.db.Exec(ctx, query, scope, key, until)
+	if err != nil {
+		return fmt.Errorf("failed to lock login: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepo) Reset(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM login_attempts WHERE scope = $1 AND key = $2`
+	_, err := r.db.Exec(ctx, query, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", translateError(err))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type MFARepo struct {
+	db *pgxpool.Pool
+}
+
+func NewMFARepo(db *pgxpool.Pool) repo.MFA {
+	return &MFARepo{db: db}
+}
+
+// GetTOTP returns nil without an error when the user never enrolled.
+func (r *MFARepo) GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTP, error) {
+	var totp entity.TOTP
+	query := `SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_totp WHERE user_id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&totp.UserID, &totp.Secret, &totp.EnabledAt, &totp.LastUsedStep, &totp.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get totp: %w", translateError(err))
+	}
+	return &totp, nil
+}
+
+// SaveTOTP stores a pending secret, replacing any earlier unconfirmed one.
+func (r *MFARepo) SaveTOTP(ctx context.Context, totp *entity.TOTP) error {
+	query := `INSERT INTO user_totp (user_id, secret) VALUES ($1, $2)
+	          ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, enabled_at = NULL, last_used_step = 0, created_at = NOW()
+	          RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, totp.UserID, totp.Secret).Scan(&totp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save totp: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *MFARepo) EnableTOTP(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE user_totp SET enabled_at = NOW() WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", translateError(err))
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		_, err = tx.Exec(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, codeHash)
+		if err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", translateError(err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit totp: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *MFARepo) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete totp: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", translateError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit totp removal: %w", translateError(err))
+	}
+	return nil
+}
+
+// UseTOTPStep records step as consumed and reports false if it, or a later
+// step, was already used, so a code cannot be replayed within its window.
+func (r *MFARepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+	result, err := r.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", translateError(err))
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := `UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	result, err := r.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", translateError(err))
+	}
+	return result.RowsAffected() == 1, nil
+}
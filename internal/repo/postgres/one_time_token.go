@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type OneTimeTokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOneTimeTokenRepo(db *pgxpool.Pool) repo.OneTimeToken {
+	return &OneTimeTokenRepo{db: db}
+}
+
+func (r *OneTimeTokenRepo) Create(ctx context.Context, token *entity.OneTimeToken, tokenHash string) error {
+	query := `INSERT INTO one_time_tokens (token_hash, user_id, purpose, email, expires_at)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, tokenHash, token.UserID, token.Purpose, token.Email, token.ExpiresAt).Scan(&token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create one-time token: %w", translateError(err))
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used in a single statement so
+// two concurrent requests can never both redeem it. It returns nil without an
+// error when no such token exists.
+func (r *OneTimeTokenRepo) Consume(ctx context.Context, tokenHash, purpose string) (*entity.OneTimeToken, error) {
+	var token entity.OneTimeToken
+	query := `UPDATE one_time_tokens SET used_at = NOW()
+	          WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	          RETURNING user_id, purpose, email, created_at, expires_at, used_at`
+	err := r.db.QueryRow(ctx, query, tokenHash, purpose).Scan(
+		&token.UserID, &token.Purpose, &token.Email, &token.CreatedAt, &token.ExpiresAt, &token.UsedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume one-time token: %w", translateError(err))
+	}
+	return &token, nil
+}
+
+func (r *OneTimeTokenRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID, purpose string) error {
+	query := `DELETE FROM one_time_tokens WHERE user_id = $1 AND purpose = $2`
+	_, err := r.db.Exec(ctx, query, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to delete one-time tokens: %w", translateError(err))
+	}
+	return nil
+}
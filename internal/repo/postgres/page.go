@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+)
+
+// keyset returns the (created_at, id) query arguments of the page's cursor;
+// both are NULL for the first page.
+func keyset(page entity.PageRequest) (*time.Time, *uuid.UUID) {
+	if page.After == nil {
+		return nil, nil
+	}
+
+	return &page.After.CreatedAt, &page.After.ID
+}
@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
+	"social/api/internal/usecase"
 )
 
 type PostRepo struct {
@@ -18,45 +21,66 @@ func NewPostRepo(db *pgxpool.Pool) repo.Post {
 	return &PostRepo{db: db}
 }
 
+// Create adds the post. Reposts and quote posts also count towards the
+// repost_count or quote_count of their original.
 func (r *PostRepo) Create(ctx context.Context, post *entity.Post) error {
-	query := `INSERT INTO posts (author_id, content, image_url) 
-	          VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, post.AuthorID, post.Content, post.ImageURL).Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create post: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO posts (author_id, content, image_url, repost_of_id, quote_of_id) 
+	          VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, post.AuthorID, post.Content, post.ImageURL, post.RepostOfID, post.QuoteOfID).Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt)
+	// ON CONFLICT DO NOTHING returns no row when the user already reposted it
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("repost %w", usecase.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create post: %w", translateError(err))
+	}
+
+	err = countOriginal(ctx, tx, post.RepostOfID, post.QuoteOfID, 1)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
 	}
 	return nil
 }
 
 func (r *PostRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Post, error) {
 	var post entity.Post
-	query := `SELECT id, author_id, content, image_url, created_at, updated_at 
+	query := `SELECT id, author_id, content, image_url, repost_of_id, quote_of_id, like_count, comment_count, repost_count, quote_count, created_at, updated_at 
 	          FROM posts WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&post.ID, &post.AuthorID, &post.Content, &post.ImageURL, &post.CreatedAt, &post.UpdatedAt)
+	err := r.db.QueryRow(ctx, query, id).Scan(postFields(&post)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get post by ID: %w", err)
+		return nil, fmt.Errorf("failed to get post by ID: %w", translateError(err))
 	}
 	return &post, nil
 }
 
-func (r *PostRepo) GetByAuthorID(ctx context.Context, authorID uuid.UUID) ([]entity.Post, error) {
+// GetByIDs returns the posts that exist among the given IDs, in no
+// particular order.
+func (r *PostRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Post, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, author_id, content, image_url, created_at, updated_at 
-		FROM posts 
-		WHERE author_id = $1 
-		ORDER BY created_at DESC`, authorID)
+		SELECT id, author_id, content, image_url, repost_of_id, quote_of_id, like_count, comment_count, repost_count, quote_count, created_at, updated_at
+		FROM posts WHERE id = ANY($1)`, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get posts by author ID: %w", err)
+		return nil, fmt.Errorf("failed to get posts by IDs: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var posts []entity.Post
 	for rows.Next() {
 		var post entity.Post
-		err := rows.Scan(&post.ID, &post.AuthorID, &post.Content, &post.ImageURL, &post.CreatedAt, &post.UpdatedAt)
+		err := rows.Scan(postFields(&post)...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan post: %w", err)
+			return nil, fmt.Errorf("failed to scan post: %w", translateError(err))
 		}
 		posts = append(posts, post)
 	}
@@ -64,30 +88,93 @@ func (r *PostRepo) GetByAuthorID(ctx context.Context, authorID uuid.UUID) ([]ent
 	return posts, nil
 }
 
-func (r *PostRepo) GetFeed(ctx context.Context, userID uuid.UUID) ([]entity.Post, error) {
+func (r *PostRepo) GetByAuthorID(ctx context.Context, authorID uuid.UUID, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
 	rows, err := r.db.Query(ctx, `
-		SELECT p.id, p.author_id, p.content, p.image_url, p.created_at, p.updated_at
-		FROM posts p
-		JOIN followers f ON p.author_id = f.user_id
-		WHERE f.follower_id = $1
-		ORDER BY p.created_at DESC
-		LIMIT 50`, userID)
+		SELECT id, author_id, content, image_url, repost_of_id, quote_of_id, like_count, comment_count, repost_count, quote_count, created_at, updated_at 
+		FROM posts 
+		WHERE author_id = $1
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2, $3::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4`, authorID, afterTime, afterID, page.Limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feed: %w", err)
+		return nil, nil, fmt.Errorf("failed to get posts by author ID: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var posts []entity.Post
 	for rows.Next() {
 		var post entity.Post
-		err := rows.Scan(&post.ID, &post.AuthorID, &post.Content, &post.ImageURL, &post.CreatedAt, &post.UpdatedAt)
+		err := rows.Scan(postFields(&post)...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan post: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan post: %w", translateError(err))
 		}
 		posts = append(posts, post)
 	}
 
-	return posts, nil
+	return nextPosts(posts, page)
+}
+
+// GetRecent leaves out reposts, whose originals are recent posts themselves
+// or were recent once.
+func (r *PostRepo) GetRecent(ctx context.Context, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
+	rows, err := r.db.Query(ctx, `
+		SELECT id, author_id, content, image_url, repost_of_id, quote_of_id, like_count, comment_count, repost_count, quote_count, created_at, updated_at
+		FROM posts
+		WHERE repost_of_id IS NULL
+		  AND ($1::timestamptz IS NULL OR (created_at, id) < ($1, $2::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`, afterTime, afterID, page.Limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recent posts: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var posts []entity.Post
+	for rows.Next() {
+		var post entity.Post
+		err := rows.Scan(postFields(&post)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan post: %w", translateError(err))
+		}
+		posts = append(posts, post)
+	}
+
+	return nextPosts(posts, page)
+}
+
+// GetRepost returns the user's repost of the post.
+func (r *PostRepo) GetRepost(ctx context.Context, authorID, postID uuid.UUID) (*entity.Post, error) {
+	var post entity.Post
+	query := `SELECT id, author_id, content, image_url, repost_of_id, quote_of_id, like_count, comment_count, repost_count, quote_count, created_at, updated_at
+	          FROM posts WHERE author_id = $1 AND repost_of_id = $2`
+	err := r.db.QueryRow(ctx, query, authorID, postID).Scan(postFields(&post)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repost: %w", translateError(err))
+	}
+	return &post, nil
+}
+
+// GetRepostedIDs reports which of the posts the user reposted.
+func (r *PostRepo) GetRepostedIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	rows, err := r.db.Query(ctx, `SELECT repost_of_id FROM posts WHERE author_id = $1 AND repost_of_id = ANY($2)`, userID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reposts: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	reposted := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var postID uuid.UUID
+		err := rows.Scan(&postID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan repost: %w", translateError(err))
+		}
+		reposted[postID] = true
+	}
+
+	return reposted, nil
 }
 
 func (r *PostRepo) Update(ctx context.Context, post *entity.Post) error {
@@ -95,19 +182,89 @@ func (r *PostRepo) Update(ctx context.Context, post *entity.Post) error {
 	          WHERE id = $3 RETURNING updated_at`
 	err := r.db.QueryRow(ctx, query, post.Content, post.ImageURL, post.ID).Scan(&post.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to update post: %w", err)
+		return fmt.Errorf("failed to update post: %w", translateError(err))
 	}
 	return nil
 }
 
+// Delete removes the post together with the reactions to it and to its
+// comments, which are not tied to it by foreign keys. Reposts and quotes of
+// the post are kept and show it as deleted.
 func (r *PostRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM posts WHERE id = $1`
-	result, err := r.db.Exec(ctx, query, id)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Wait for reactions that are being added, and keep new ones out
+	_, err = tx.Exec(ctx, `SELECT 1 FROM posts WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		return fmt.Errorf("failed to lock post: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM reactions
+		WHERE (target_type = 'post' AND target_id = $1)
+		   OR (target_type = 'comment' AND target_id IN (SELECT id FROM comments WHERE post_id = $1))`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reactions: %w", translateError(err))
+	}
+
+	var repostOfID, quoteOfID *uuid.UUID
+	query := `DELETE FROM posts WHERE id = $1 RETURNING repost_of_id, quote_of_id`
+	err = tx.QueryRow(ctx, query, id).Scan(&repostOfID, &quoteOfID)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", translateError(err))
+	}
+
+	err = countOriginal(ctx, tx, repostOfID, quoteOfID, -1)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete post: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+// countOriginal adds delta to the repost or quote count of the post's
+// original, if it has one that still exists.
+func countOriginal(ctx context.Context, tx pgx.Tx, repostOfID, quoteOfID *uuid.UUID, delta int) error {
+	var err error
+	switch {
+	case repostOfID != nil:
+		_, err = tx.Exec(ctx, `UPDATE posts SET repost_count = repost_count + $2 WHERE id = $1`, *repostOfID, delta)
+	case quoteOfID != nil:
+		_, err = tx.Exec(ctx, `UPDATE posts SET quote_count = quote_count + $2 WHERE id = $1`, *quoteOfID, delta)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("post not found")
+	if err != nil {
+		return fmt.Errorf("failed to update repost count: %w", translateError(err))
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// postFields lists the destinations of the post columns in the order the
+// queries select them: id, author_id, content, image_url, repost_of_id,
+// quote_of_id, like_count, comment_count, repost_count, quote_count,
+// created_at and updated_at.
+func postFields(post *entity.Post) []any {
+	return []any{
+		&post.ID, &post.AuthorID, &post.Content, &post.ImageURL, &post.RepostOfID, &post.QuoteOfID,
+		&post.LikeCount, &post.CommentCount, &post.RepostCount, &post.QuoteCount, &post.CreatedAt, &post.UpdatedAt,
+	}
+}
+
+// nextPosts drops the extra row fetched to detect a following page and
+// returns the cursor to that page.
+func nextPosts(posts []entity.Post, page entity.PageRequest) ([]entity.Post, *entity.Cursor, error) {
+	if len(posts) <= page.Limit {
+		return posts, nil, nil
+	}
+
+	posts = posts[:page.Limit]
+	last := posts[len(posts)-1]
+	return posts, &entity.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/internal/usecase"
+)
+
+type ReactionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReactionRepo(db *pgxpool.Pool) repo.Reaction {
+	return &ReactionRepo{db: db}
+}
+
+// reactionTargets locks the target of a reaction, and for comments also their
+// post, so that it cannot be deleted while the reaction is added.
+var reactionTargets = map[entity.ReactionTargetType]string{
+	entity.ReactionTargetPost: `SELECT 1 FROM posts WHERE id = $3 FOR SHARE`,
+	entity.ReactionTargetComment: `SELECT 1 FROM comments c JOIN posts p ON p.id = c.post_id
+	                               WHERE c.id = $3 AND c.deleted_at IS NULL FOR SHARE`,
+}
+
+// Create adds the reaction if its target still exists. Likes are 👍
+// reactions to posts, so those also keep the post's like_count in step.
+func (r *ReactionRepo) Create(ctx context.Context, reaction *entity.Reaction) error {
+	target, ok := reactionTargets[reaction.TargetType]
+	if !ok {
+		return fmt.Errorf("reaction target %w", usecase.ErrNotFound)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO reactions (user_id, target_type, target_id, emoji)
+	          SELECT $1, $2, $3, $4 WHERE EXISTS (` + target + `)
+	          ON CONFLICT DO NOTHING RETURNING created_at`
+	err = tx.QueryRow(ctx, query, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji).Scan(&reaction.CreatedAt)
+	// No row comes back either when the target is gone or, through ON
+	// CONFLICT DO NOTHING, when the reaction already exists
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		query = `SELECT EXISTS (SELECT 1 FROM reactions WHERE user_id = $1 AND target_type = $2 AND target_id = $3 AND emoji = $4)`
+		err = tx.QueryRow(ctx, query, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check reaction: %w", translateError(err))
+		}
+		if exists {
+			return fmt.Errorf("reaction %w", usecase.ErrConflict)
+		}
+		return fmt.Errorf("reaction target %w", usecase.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create reaction: %w", translateError(err))
+	}
+
+	if isLike(reaction.TargetType, reaction.Emoji) {
+		_, err = tx.Exec(ctx, `UPDATE posts SET like_count = like_count + 1 WHERE id = $1`, reaction.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to update like count: %w", translateError(err))
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *ReactionRepo) Delete(ctx context.Context, userID uuid.UUID, targetType entity.ReactionTargetType, targetID uuid.UUID, emoji string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `DELETE FROM reactions WHERE user_id = $1 AND target_type = $2 AND target_id = $3 AND emoji = $4`
+	result, err := tx.Exec(ctx, query, userID, targetType, targetID, emoji)
+	if err != nil {
+		return fmt.Errorf("failed to delete reaction: %w", translateError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("reaction %w", usecase.ErrNotFound)
+	}
+
+	if isLike(targetType, emoji) {
+		_, err = tx.Exec(ctx, `UPDATE posts SET like_count = like_count - 1 WHERE id = $1`, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to update like count: %w", translateError(err))
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+// GetCounts counts the reactions to each target per emoji.
+func (r *ReactionRepo) GetCounts(ctx context.Context, targetType entity.ReactionTargetType, targetIDs []uuid.UUID) (map[uuid.UUID]map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT target_id, emoji, COUNT(*)
+		FROM reactions
+		WHERE target_type = $1 AND target_id = ANY($2)
+		GROUP BY target_id, emoji`, targetType, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]map[string]int, len(targetIDs))
+	for rows.Next() {
+		var targetID uuid.UUID
+		var emoji string
+		var count int
+		err := rows.Scan(&targetID, &emoji, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction count: %w", translateError(err))
+		}
+		if counts[targetID] == nil {
+			counts[targetID] = make(map[string]int)
+		}
+		counts[targetID][emoji] = count
+	}
+
+	return counts, nil
+}
+
+// GetUserReactions returns the emoji the user reacted to each target with.
+func (r *ReactionRepo) GetUserReactions(ctx context.Context, userID uuid.UUID, targetType entity.ReactionTargetType, targetIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT target_id, emoji
+		FROM reactions
+		WHERE user_id = $1 AND target_type = $2 AND target_id = ANY($3)
+		ORDER BY created_at`, userID, targetType, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user reactions: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	reactions := make(map[uuid.UUID][]string)
+	for rows.Next() {
+		var targetID uuid.UUID
+		var emoji string
+		err := rows.Scan(&targetID, &emoji)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", translateError(err))
+		}
+		reactions[targetID] = append(reactions[targetID], emoji)
+	}
+
+	return reactions, nil
+}
+
+func isLike(targetType entity.ReactionTargetType, emoji string) bool {
+	return targetType == entity.ReactionTargetPost && emoji == entity.LikeEmoji
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/internal/usecase"
+)
+
+type SessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepo(db *pgxpool.Pool) repo.Session {
+	return &SessionRepo{db: db}
+}
+
+func (r *SessionRepo) Create(ctx context.Context, session *entity.Session, tokenHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO sessions (user_id, user_agent, ip_address, expires_at) 
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at, last_used_at`
+	err = tx.QueryRow(ctx, query, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt).Scan(
+		&session.ID, &session.CreatedAt, &session.LastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)`, tokenHash, session.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", translateError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit session: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *SessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Session, error) {
+	var session entity.Session
+	query := `SELECT id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at 
+	          FROM sessions WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &session.RevokedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session by ID: %w", translateError(err))
+	}
+	return &session, nil
+}
+
+func (r *SessionRepo) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Session, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at 
+		FROM sessions 
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions by user ID: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var sessions []entity.Session
+	for rows.Next() {
+		var session entity.Session
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &session.RevokedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", translateError(err))
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	query := `SELECT token_hash, session_id, created_at, rotated_at FROM refresh_tokens WHERE token_hash = $1`
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(&token.TokenHash, &token.SessionID, &token.CreatedAt, &token.RotatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", translateError(err))
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken marks oldHash as used and issues newHash for the same
+// session. It reports false when oldHash had already been rotated, which
+// happens when two requests race with the same token.
+func (r *SessionRepo) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	var sessionID uuid.UUID
+	query := `UPDATE refresh_tokens SET rotated_at = NOW() 
+	          WHERE token_hash = $1 AND rotated_at IS NULL RETURNING session_id`
+	err = tx.QueryRow(ctx, query, oldHash).Scan(&sessionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)`, newHash, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create refresh token: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE sessions SET last_used_at = NOW() WHERE id = $1`, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to touch session: %w", translateError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit refresh token rotation: %w", translateError(err))
+	}
+	return true, nil
+}
+
+func (r *SessionRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", translateError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session %w", usecase.ErrNotFound)
+	}
+	return nil
+}
+
+// RevokeAllByUserID revokes every session of the user except exceptID, which
+// may be uuid.Nil to revoke them all.
+func (r *SessionRepo) RevokeAllByUserID(ctx context.Context, userID, exceptID uuid.UUID) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID, exceptID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", translateError(err))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type TimelineRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTimelineRepo(db *pgxpool.Pool) repo.Timeline {
+	return &TimelineRepo{db: db}
+}
+
+// FanOut copies the post into the timelines of its author's followers, unless
+// the author has maxFollowers or more; those posts are merged in on read.
+func (r *TimelineRepo) FanOut(ctx context.Context, post *entity.Post, maxFollowers int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT f.follower_id, $1, $2, $3
+		FROM followers f
+		WHERE f.user_id = $2
+		  AND (SELECT follower_count FROM users WHERE id = $2) < $4
+		ON CONFLICT DO NOTHING`, post.ID, post.AuthorID, post.CreatedAt, maxFollowers)
+	if err != nil {
+		return fmt.Errorf("failed to fan out post: %w", translateError(err))
+	}
+	return nil
+}
+
+// Backfill copies the author's latest posts into the user's timeline.
+func (r *TimelineRepo) Backfill(ctx context.Context, userID, authorID uuid.UUID, limit int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT $1, p.id, p.author_id, p.created_at
+		FROM posts p
+		WHERE p.author_id = $2
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $3
+		ON CONFLICT DO NOTHING`, userID, authorID, limit)
+	if err != nil {
+		return fmt.Errorf("failed to backfill timeline: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *TimelineRepo) RemoveAuthor(ctx context.Context, userID, authorID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM timelines WHERE user_id = $1 AND author_id = $2`, userID, authorID)
+	if err != nil {
+		return fmt.Errorf("failed to remove author from timeline: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *TimelineRepo) IsBuilt(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var built bool
+	query := `SELECT EXISTS(SELECT 1 FROM timeline_builds WHERE user_id = $1)`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&built)
+	if err != nil {
+		return false, fmt.Errorf("failed to check timeline: %w", translateError(err))
+	}
+	return built, nil
+}
+
+// Rebuild replaces the user's unbuilt timeline with the latest posts of the
+// authors they follow, the same way fan-out would have filled it. Rebuilds of
+// one user run one at a time, and a timeline built meanwhile is kept.
+func (r *TimelineRepo) Rebuild(ctx context.Context, userID uuid.UUID, limit, maxFollowers int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent first reads would otherwise each rebuild the timeline
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text))`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to lock timeline: %w", translateError(err))
+	}
+
+	// A concurrent first read may have rebuilt it while this one waited
+	var built bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM timeline_builds WHERE user_id = $1)`, userID).Scan(&built)
+	if err != nil {
+		return fmt.Errorf("failed to check timeline: %w", translateError(err))
+	}
+	if built {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM timelines WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear timeline: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT $1, p.id, p.author_id, p.created_at
+		FROM followers f
+		JOIN users a ON a.id = f.user_id AND a.follower_count < $3
+		JOIN posts p ON p.author_id = f.user_id
+		WHERE f.follower_id = $1
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $2
+		ON CONFLICT DO NOTHING`, userID, limit, maxFollowers)
+	if err != nil {
+		return fmt.Errorf("failed to fill timeline: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO timeline_builds (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET built_at = NOW()`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark timeline as built: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+// Get reads a page of the user's feed: their timeline, merged with their own
+// posts and the posts of followed authors with maxFollowers or more
+// followers, which are never fanned out. Timeline rows of authors the user no
+// longer follows are left out, in case an update was applied late. Reposts are posts of the reposting
+// user, so they come in the same way.
+func (r *TimelineRepo) Get(ctx context.Context, userID uuid.UUID, page entity.PageRequest, maxFollowers int) ([]entity.FeedItem, *entity.Cursor, error) {
+	afterTime, afterID := keyset(page)
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.username, a.name, a.profile_picture_url,
+		       p.id, p.author_id, p.content, p.image_url, p.repost_of_id, p.quote_of_id, p.like_count, p.comment_count, p.repost_count, p.quote_count, p.created_at, p.updated_at
+		FROM (
+			(SELECT t.post_id AS id, t.created_at
+			 FROM timelines t
+			 WHERE t.user_id = $1
+			   AND EXISTS (SELECT 1 FROM followers f WHERE f.user_id = t.author_id AND f.follower_id = $1)
+			   AND ($2::timestamptz IS NULL OR (t.created_at, t.post_id) < ($2, $3::uuid))
+			 ORDER BY t.created_at DESC, t.post_id DESC
+			 LIMIT $4)
+			UNION
+			(SELECT p.id, p.created_at
+			 FROM followers f
+			 JOIN users a ON a.id = f.user_id AND a.follower_count >= $5
+			 JOIN posts p ON p.author_id = f.user_id
+			 WHERE f.follower_id = $1
+			   AND ($2::timestamptz IS NULL OR (p.created_at, p.id) < ($2, $3::uuid))
+			 ORDER BY p.created_at DESC, p.id DESC
+			 LIMIT $4)
+			UNION
+			(SELECT p.id, p.created_at
+			 FROM posts p
+			 WHERE p.author_id = $1
+			   AND ($2::timestamptz IS NULL OR (p.created_at, p.id) < ($2, $3::uuid))
+			 ORDER BY p.created_at DESC, p.id DESC
+			 LIMIT $4)
+		) items
+		JOIN posts p ON p.id = items.id
+		JOIN users a ON a.id = p.author_id
+		ORDER BY items.created_at DESC, items.id DESC
+		LIMIT $4`, userID, afterTime, afterID, page.Limit+1, maxFollowers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get timeline: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var items []entity.FeedItem
+	for rows.Next() {
+		item := entity.FeedItem{Type: entity.FeedItemPost}
+		err := rows.Scan(append(
+			[]any{&item.Actor.ID, &item.Actor.Username, &item.Actor.Name, &item.Actor.ImageURL},
+			postFields(&item.Post)...)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan feed item: %w", translateError(err))
+		}
+		// The use cases swap in the reposted post once they have loaded it
+		if item.Post.RepostOfID != nil {
+			item.Type = entity.FeedItemRepost
+		}
+		item.ID = item.Post.ID
+		item.CreatedAt = item.Post.CreatedAt
+		items = append(items, item)
+	}
+
+	return nextItems(items, page)
+}
+
+func nextItems(items []entity.FeedItem, page entity.PageRequest) ([]entity.FeedItem, *entity.Cursor, error) {
+	if len(items) <= page.Limit {
+		return items, nil, nil
+	}
+
+	items = items[:page.Limit]
+	last := items[len(items)-1]
+	return items, &entity.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/internal/usecase"
+)
+
+type TokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenRepo(db *pgxpool.Pool) repo.Token {
+	return &TokenRepo{db: db}
+}
+
+func (r *TokenRepo) Create(ctx context.Context, token *entity.Token, tokenHash string) error {
+	query := `INSERT INTO tokens (user_id, name, token_hash, scopes, expires_at) 
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, token.UserID, token.Name, tokenHash, token.Scopes, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *TokenRepo) GetByHash(ctx context.Context, tokenHash string) (*entity.Token, error) {
+	var token entity.Token
+	query := `SELECT id, user_id, name, scopes, last_used_at, expires_at, created_at, revoked_at 
+	          FROM tokens WHERE token_hash = $1`
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.Name, &token.Scopes,
+		&token.LastUsedAt, &token.ExpiresAt, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token by hash: %w", translateError(err))
+	}
+	return &token, nil
+}
+
+func (r *TokenRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Token, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, name, scopes, last_used_at, expires_at, created_at, revoked_at 
+		FROM tokens 
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens by user ID: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var tokens []entity.Token
+	for rows.Next() {
+		var token entity.Token
+		err := rows.Scan(
+			&token.ID, &token.UserID, &token.Name, &token.Scopes,
+			&token.LastUsedAt, &token.ExpiresAt, &token.CreatedAt, &token.RevokedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", translateError(err))
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func (r *TokenRepo) Revoke(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE tokens SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", translateError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("token %w", usecase.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *TokenRepo) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE tokens SET last_used_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update token last used: %w", translateError(err))
+	}
+	return nil
+}
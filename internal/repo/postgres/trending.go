@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+type TrendingRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTrendingRepo(db *pgxpool.Pool) repo.Trending {
+	return &TrendingRepo{db: db}
+}
+
+// GetEngagement counts reactions once and comments, reposts and quotes
+// twice. Reactions are not tied to posts by foreign keys, so only posts that
+// still exist are returned.
+func (r *TrendingRepo) GetEngagement(ctx context.Context, since time.Time) ([]entity.PostEngagement, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.id, p.created_at, SUM(engagement.weight)
+		FROM (
+			SELECT target_id AS post_id, 1 AS weight FROM reactions WHERE target_type = 'post' AND created_at >= $1
+			UNION ALL
+			SELECT post_id, 2 AS weight FROM comments WHERE created_at >= $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT o.id, 2 AS weight FROM posts s JOIN posts o ON o.id = COALESCE(s.repost_of_id, s.quote_of_id) WHERE s.created_at >= $1
+		) engagement
+		JOIN posts p ON p.id = engagement.post_id
+		GROUP BY p.id, p.created_at`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get engagement: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var engagement []entity.PostEngagement
+	for rows.Next() {
+		var e entity.PostEngagement
+		err := rows.Scan(&e.PostID, &e.CreatedAt, &e.Engagement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan engagement: %w", translateError(err))
+		}
+		engagement = append(engagement, e)
+	}
+
+	return engagement, nil
+}
+
+func (r *TrendingRepo) Replace(ctx context.Context, scores []entity.TrendingScore) error {
+	postIDs := make([]uuid.UUID, len(scores))
+	values := make([]float64, len(scores))
+	for i, score := range scores {
+		postIDs[i] = score.PostID
+		values[i] = score.Score
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM trending_posts`)
+	if err != nil {
+		return fmt.Errorf("failed to clear trending posts: %w", translateError(err))
+	}
+
+	// Posts deleted since their engagement was read are skipped
+	_, err = tx.Exec(ctx, `
+		INSERT INTO trending_posts (post_id, score)
+		SELECT s.post_id, s.score
+		FROM unnest($1::uuid[], $2::float8[]) AS s(post_id, score)
+		JOIN posts p ON p.id = s.post_id`, postIDs, values)
+	if err != nil {
+		return fmt.Errorf("failed to store trending posts: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *TrendingRepo) Get(ctx context.Context, limit int) ([]entity.Post, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.id, p.author_id, p.content, p.image_url, p.repost_of_id, p.quote_of_id, p.like_count, p.comment_count, p.repost_count, p.quote_count, p.created_at, p.updated_at
+		FROM trending_posts t
+		JOIN posts p ON p.id = t.post_id
+		ORDER BY t.score DESC, t.post_id
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending posts: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var posts []entity.Post
+	for rows.Next() {
+		var post entity.Post
+		err := rows.Scan(postFields(&post)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", translateError(err))
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
@@ -2,9 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
@@ -23,69 +26,191 @@ func (r *UserRepo) Create(ctx context.Context, user *entity.User) error {
 	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
 	err := r.db.QueryRow(ctx, query, user.Name, user.Username, user.Email, user.Password, user.Bio, user.ImageURL).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return fmt.Errorf("failed to create user: %w", translateError(err))
 	}
 	return nil
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, name, username, email, password_hash, bio, profile_picture_url, created_at, updated_at 
+	query := `SELECT id, name, username, email, email_verified_at, password_hash, roles, bio, profile_picture_url, private_likes, created_at, updated_at 
 	          FROM users WHERE id = $1`
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-		&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+		&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+		return nil, fmt.Errorf("failed to get user by ID: %w", translateError(err))
 	}
 	return &user, nil
 }
 
+func (r *UserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.User, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name, username, email, email_verified_at, password_hash, roles, bio, profile_picture_url, private_likes, created_at, updated_at
+		FROM users WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by ID: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+			&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", translateError(err))
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
 func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, name, username, email, password_hash, bio, profile_picture_url, created_at, updated_at 
+	query := `SELECT id, name, username, email, email_verified_at, password_hash, roles, bio, profile_picture_url, private_likes, created_at, updated_at 
 	          FROM users WHERE email = $1`
 	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-		&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+		&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
+		return nil, fmt.Errorf("failed to get user by email: %w", translateError(err))
 	}
 	return &user, nil
 }
 
 func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, name, username, email, password_hash, bio, profile_picture_url, created_at, updated_at 
+	query := `SELECT id, name, username, email, email_verified_at, password_hash, roles, bio, profile_picture_url, private_likes, created_at, updated_at 
 	          FROM users WHERE username = $1`
 	err := r.db.QueryRow(ctx, query, username).Scan(
-		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-		&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+		&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by username: %w", err)
+		return nil, fmt.Errorf("failed to get user by username: %w", translateError(err))
 	}
 	return &user, nil
 }
 
 func (r *UserRepo) Update(ctx context.Context, user *entity.User) error {
-	query := `UPDATE users SET name = $1, bio = $2, profile_picture_url = $3, updated_at = NOW() 
-	          WHERE id = $4 RETURNING updated_at`
-	err := r.db.QueryRow(ctx, query, user.Name, user.Bio, user.ImageURL, user.ID).Scan(&user.UpdatedAt)
+	query := `UPDATE users SET name = $1, bio = $2, profile_picture_url = $3, private_likes = $4, updated_at = NOW() 
+	          WHERE id = $5 RETURNING updated_at`
+	err := r.db.QueryRow(ctx, query, user.Name, user.Bio, user.ImageURL, user.PrivateLikes, user.ID).Scan(&user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", translateError(err))
+	}
+	return nil
+}
+
+// MarkEmailVerified only succeeds while the user still has the given email,
+// so a token sent to a previous address cannot verify a new one.
+func (r *UserRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	query := `UPDATE users SET email_verified_at = NOW(), updated_at = NOW() WHERE id = $1 AND email = $2`
+	tag, err := r.db.Exec(ctx, query, id, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark email verified: %w", translateError(err))
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// UpdateEmail replaces the email and marks it as unverified.
+func (r *UserRepo) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	query := `UPDATE users SET email = $1, email_verified_at = NULL, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, email, id)
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return fmt.Errorf("failed to update email: %w", translateError(err))
+	}
+	return nil
+}
+
+// UpdateUsername renames the user and records the old name in username_history.
+func (r *UserRepo) UpdateUsername(ctx context.Context, id uuid.UUID, username string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO username_history (user_id, username)
+		SELECT id, username FROM users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record username history: %w", translateError(err))
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET username = $1, updated_at = NOW() WHERE id = $2`, username, id)
+	if err != nil {
+		return fmt.Errorf("failed to update username: %w", translateError(err))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
+
+// GetByPreviousUsername returns the user who most recently gave up username.
+func (r *UserRepo) GetByPreviousUsername(ctx context.Context, username string) (*entity.User, error) {
+	var user entity.User
+	query := `SELECT u.id, u.name, u.username, u.email, u.email_verified_at, u.password_hash, u.roles, u.bio, u.profile_picture_url, u.private_likes, u.created_at, u.updated_at
+	          FROM username_history h
+	          JOIN users u ON u.id = h.user_id
+	          WHERE h.username = $1
+	          ORDER BY h.changed_at DESC
+	          LIMIT 1`
+	err := r.db.QueryRow(ctx, query, username).Scan(
+		&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+		&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by previous username: %w", translateError(err))
+	}
+	return &user, nil
+}
+
+// LastUsernameChange returns nil without an error if the user was never renamed.
+func (r *UserRepo) LastUsernameChange(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	var changedAt time.Time
+	query := `SELECT changed_at FROM username_history WHERE user_id = $1 ORDER BY changed_at DESC LIMIT 1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&changedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last username change: %w", translateError(err))
+	}
+	return &changedAt, nil
+}
+
+func (r *UserRepo) SetRoles(ctx context.Context, id uuid.UUID, roles []string) error {
+	query := `UPDATE users SET roles = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, roles, id)
+	if err != nil {
+		return fmt.Errorf("failed to set roles: %w", translateError(err))
 	}
 	return nil
 }
 
 func (r *UserRepo) Search(ctx context.Context, query string) ([]entity.User, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, name, username, email, password_hash, bio, profile_picture_url, created_at, updated_at 
+		SELECT id, name, username, email, email_verified_at, password_hash, roles, bio, profile_picture_url, private_likes, created_at, updated_at 
 		FROM users 
 		WHERE name ILIKE $1 OR username ILIKE $1
 		ORDER BY created_at DESC
 		LIMIT 20`, "%"+query+"%")
 	if err != nil {
-		return nil, fmt.Errorf("failed to search users: %w", err)
+		return nil, fmt.Errorf("failed to search users: %w", translateError(err))
 	}
 	defer rows.Close()
 
@@ -93,13 +218,13 @@ func (r *UserRepo) Search(ctx context.Context, query string) ([]entity.User, err
 	for rows.Next() {
 		var user entity.User
 		err := rows.Scan(
-			&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-			&user.Bio, &user.ImageURL, &user.CreatedAt, &user.UpdatedAt)
+			&user.ID, &user.Name, &user.Username, &user.Email, &user.EmailVerifiedAt, &user.Password, &user.Roles,
+			&user.Bio, &user.ImageURL, &user.PrivateLikes, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, fmt.Errorf("failed to scan user: %w", translateError(err))
 		}
 		users = append(users, user)
 	}
 
 	return users, nil
-}
\ No newline at end of file
+}
@@ -3,37 +3,80 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
 )
 
+var (
+	ErrCommentNotFound  = NewError(ErrNotFound, "comment_not_found", "comment not found")
+	ErrCommentForbidden = NewError(ErrForbidden, "comment_forbidden", "you can only delete your own comments or comments on your posts")
+	ErrCommentTooDeep   = NewError(ErrValidation, "comment_too_deep", fmt.Sprintf("replies can only be nested %d levels deep", maxCommentDepth))
+	ErrCommentNotEditor = NewError(ErrForbidden, "comment_edit_forbidden", "you can only edit your own comments")
+	ErrEditWindowClosed = NewError(ErrForbidden, "edit_window_closed", "the comment can no longer be edited")
+	ErrRevisionsHidden  = NewError(ErrForbidden, "revisions_forbidden", "only moderators can read the revisions of a comment")
+)
+
 type commentService struct {
 	commentRepo repo.Comment
 	userRepo    repo.User
 	postRepo    repo.Post
+	editWindow  time.Duration
+	gate        verifiedGate
 }
 
-func NewCommentUseCase(commentRepo repo.Comment, userRepo repo.User, postRepo repo.Post) Comment {
+// NewCommentUseCase lets authors edit their comments for editWindow after
+// writing them.
+func NewCommentUseCase(commentRepo repo.Comment, userRepo repo.User, postRepo repo.Post, editWindow time.Duration, unverified UnverifiedAccess) Comment {
 	return &commentService{
 		commentRepo: commentRepo,
 		userRepo:    userRepo,
 		postRepo:    postRepo,
+		editWindow:  editWindow,
+		gate:        verifiedGate{userRepo: userRepo, access: unverified},
 	}
 }
 
-func (s *commentService) AddComment(ctx context.Context, postID, userID uuid.UUID, content string) (*entity.Comment, error) {
+// AddComment comments on the post, or replies to parentID unless that is nil.
+func (s *commentService) AddComment(ctx context.Context, postID, userID uuid.UUID, content string, parentID *uuid.UUID) (*entity.Comment, error) {
+	err := validateText("content", content, true, maxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.gate.canWrite(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Verify post exists
-	_, err := s.postRepo.GetByID(ctx, postID)
+	_, err = s.postRepo.GetByID(ctx, postID)
 	if err != nil {
-		return nil, fmt.Errorf("post not found: %w", err)
+		return nil, lookupError(err, ErrPostNotFound)
 	}
 
 	comment := &entity.Comment{
-		PostID:   postID,
-		AuthorID: userID,
-		Content:  content,
+		PostID:          postID,
+		AuthorID:        userID,
+		ParentCommentID: parentID,
+		Content:         content,
+	}
+
+	if parentID != nil {
+		parent, err := s.visibleComment(ctx, postID, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		// Placeholders of deleted comments take no new replies
+		if parent.IsDeleted() {
+			return nil, ErrCommentNotFound
+		}
+		if parent.Depth >= maxCommentDepth {
+			return nil, ErrCommentTooDeep
+		}
+		comment.Depth = parent.Depth + 1
 	}
 
 	err = s.commentRepo.Create(ctx, comment)
@@ -44,29 +87,160 @@ func (s *commentService) AddComment(ctx context.Context, postID, userID uuid.UUI
 	return comment, nil
 }
 
-func (s *commentService) GetComments(ctx context.Context, postID uuid.UUID) ([]entity.Comment, error) {
+func (s *commentService) GetComments(ctx context.Context, postID uuid.UUID, limit int, cursor string) ([]entity.Comment, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Verify post exists
-	_, err := s.postRepo.GetByID(ctx, postID)
+	_, err = s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, "", lookupError(err, ErrPostNotFound)
+	}
+
+	comments, next, err := s.commentRepo.GetByPostID(ctx, postID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return comments, encodeCursor(next), nil
+}
+
+// GetThread returns the comment followed by its replies, in the same order
+// as GetComments.
+func (s *commentService) GetThread(ctx context.Context, postID, commentID uuid.UUID, limit int, cursor string) ([]entity.Comment, string, error) {
+	page, err := pageRequest(limit, cursor)
 	if err != nil {
-		return nil, fmt.Errorf("post not found: %w", err)
+		return nil, "", err
 	}
 
-	comments, err := s.commentRepo.GetByPostID(ctx, postID)
+	_, err = s.visibleComment(ctx, postID, commentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comments: %w", err)
+		return nil, "", err
 	}
 
-	return comments, nil
+	comments, next, err := s.commentRepo.GetThread(ctx, postID, commentID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	return comments, encodeCursor(next), nil
 }
 
-func (s *commentService) DeleteComment(ctx context.Context, commentID, userID uuid.UUID) error {
-	// In a real implementation, you would fetch the comment to verify ownership
-	// For now, we'll just delete it directly
-	
-	err := s.commentRepo.Delete(ctx, commentID)
+// EditComment replaces the content of the user's own comment. The version it
+// replaces is kept, for moderators to read.
+func (s *commentService) EditComment(ctx context.Context, postID, commentID, userID uuid.UUID, content string) (*entity.Comment, error) {
+	err := validateText("content", content, true, maxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, lookupError(err, ErrCommentNotFound)
+	}
+	if comment.PostID != postID || comment.IsDeleted() {
+		return nil, ErrCommentNotFound
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrCommentNotEditor
+	}
+	if time.Since(comment.CreatedAt) > s.editWindow {
+		return nil, ErrEditWindowClosed
+	}
+
+	err = s.gate.canWrite(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// An unchanged comment needs no revision
+	if comment.Content == content {
+		return comment, nil
+	}
+
+	comment.Content = content
+	err = s.commentRepo.Update(ctx, comment)
+	if err != nil {
+		return nil, lookupError(err, ErrCommentNotFound)
+	}
+
+	return comment, nil
+}
+
+// GetCommentRevisions returns the prior versions of a comment, oldest first.
+// Only moderators may read them.
+func (s *commentService) GetCommentRevisions(ctx context.Context, postID, commentID, userID uuid.UUID) ([]entity.CommentRevision, error) {
+	moderator, err := isModerator(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !moderator {
+		return nil, ErrRevisionsHidden
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, lookupError(err, ErrCommentNotFound)
+	}
+	if comment.PostID != postID {
+		return nil, ErrCommentNotFound
+	}
+
+	revisions, err := s.commentRepo.GetRevisions(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// DeleteComment lets the comment's author, the post's author and moderators
+// remove a comment.
+func (s *commentService) DeleteComment(ctx context.Context, postID, commentID, userID uuid.UUID) error {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return lookupError(err, ErrCommentNotFound)
+	}
+	if comment.PostID != postID || comment.IsDeleted() {
+		return ErrCommentNotFound
+	}
+
+	if comment.AuthorID != userID {
+		post, err := s.postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return lookupError(err, ErrCommentNotFound)
+		}
+
+		if post.AuthorID != userID {
+			moderator, err := isModerator(ctx, s.userRepo, userID)
+			if err != nil {
+				return err
+			}
+			if !moderator {
+				return ErrCommentForbidden
+			}
+		}
+	}
+
+	err = s.commentRepo.Delete(ctx, postID, commentID)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// visibleComment looks up a comment of the post that is still shown, either
+// as itself or as the placeholder of a deleted comment with replies.
+func (s *commentService) visibleComment(ctx context.Context, postID, commentID uuid.UUID) (*entity.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, lookupError(err, ErrCommentNotFound)
+	}
+	if comment.PostID != postID || (comment.IsDeleted() && comment.ReplyCount == 0) {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"social/api/internal/entity"
+)
+
+var (
+	ErrInvalidPassword       = NewError(ErrForbidden, "invalid_password", "current password is incorrect")
+	ErrEmailTaken            = NewError(ErrConflict, "email_taken", "user with this email already exists")
+	ErrUsernameTaken         = NewError(ErrConflict, "username_taken", "user with this username already exists")
+	ErrUsernameChangeTooSoon = NewError(ErrRateLimited, "username_change_too_soon", "username was changed recently, try again later")
+)
+
+// ChangePassword sets a new password and signs out every other session, so
+// whoever knew the old password loses access. sessionID is the caller's own
+// session and is kept; it is uuid.Nil for personal access tokens.
+func (s *userService) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string, client entity.Client) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, ErrUserNotFound)
+	}
+
+	err = s.confirmPassword(ctx, user, currentPassword, client)
+	if err != nil {
+		return err
+	}
+
+	err = validatePassword("new_password", newPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return s.sessions.RevokeOtherSessions(ctx, userID, sessionID)
+}
+
+// ChangeEmail switches to a new address, which has to be verified again.
+func (s *userService) ChangeEmail(ctx context.Context, userID uuid.UUID, password, email string, client entity.Client) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	err = s.confirmPassword(ctx, user, password, client)
+	if err != nil {
+		return nil, err
+	}
+
+	email = strings.TrimSpace(email)
+	err = validateEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if email != user.Email {
+		_, err = s.userRepo.GetByEmail(ctx, email)
+		if err == nil {
+			return nil, ErrEmailTaken
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+
+		err = s.userRepo.UpdateEmail(ctx, userID, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update email: %w", err)
+		}
+
+		user.Email = email
+		user.EmailVerifiedAt = nil
+
+		err = s.verification.SendVerification(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Clear password before returning
+	user.Password = ""
+	return user, nil
+}
+
+// confirmPassword checks the current password of a signed in user. Failures
+// count towards the same lockout as logins, so a stolen session cannot be
+// used to guess the password.
+func (s *userService) confirmPassword(ctx context.Context, user *entity.User, password string, client entity.Client) error {
+	err := s.guard.Check(ctx, user.Email, client.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		err = s.guard.Fail(ctx, &user.ID, user.Email, client.IPAddress)
+		if err != nil {
+			return err
+		}
+		return ErrInvalidPassword
+	}
+
+	return s.guard.Succeed(ctx, user.Email)
+}
+
+// ChangeUsername renames the user at most once per configured interval. The
+// old name keeps redirecting to the account until someone else claims it.
+func (s *userService) ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	username = strings.TrimSpace(username)
+	err = validateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if username == user.Username {
+		// Clear password before returning
+		user.Password = ""
+		return user, nil
+	}
+
+	lastChange, err := s.userRepo.LastUsernameChange(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username history: %w", err)
+	}
+	if lastChange != nil && time.Since(*lastChange) < s.usernameInterval {
+		return nil, ErrUsernameChangeTooSoon
+	}
+
+	_, err = s.userRepo.GetByUsername(ctx, username)
+	if err == nil {
+		return nil, ErrUsernameTaken
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+
+	err = s.userRepo.UpdateUsername(ctx, userID, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update username: %w", err)
+	}
+
+	user.Username = username
+
+	// Clear password before returning
+	user.Password = ""
+	return user, nil
+}
+
+// FindRenamedUser returns the account that used to be called username.
+func (s *userService) FindRenamedUser(ctx context.Context, username string) (*entity.User, error) {
+	user, err := s.userRepo.GetByPreviousUsername(ctx, username)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	// Clear password before returning
+	user.Password = ""
+	return user, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import "errors"
+
+// Error kinds. Every error a use case returns on purpose wraps one of these,
+// so transports can pick a status without knowing each specific error.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+var (
+	ErrUserNotFound       = NewError(ErrNotFound, "user_not_found", "user not found")
+	ErrPostNotFound       = NewError(ErrNotFound, "post_not_found", "post not found")
+	ErrPostForbidden      = NewError(ErrForbidden, "post_forbidden", "you can only change your own posts")
+	ErrInvalidCredentials = NewError(ErrUnauthorized, "invalid_credentials", "invalid credentials")
+)
+
+// Error is an error meant for clients: Code is stable and machine readable,
+// Message is human readable and Details carries per-field information.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Details map[string]string
+	err     error
+}
+
+func NewError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.err != nil {
+		return []error{e.Kind, e.err}
+	}
+
+	return []error{e.Kind}
+}
+
+// WithDetails returns a copy of e carrying details. The copy still matches e
+// with errors.Is.
+func (e *Error) WithDetails(details map[string]string) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Details: details, err: e}
+}
+
+// ValidationError reports a single invalid field.
+func ValidationError(field, message string) *Error {
+	return NewError(ErrValidation, "validation_failed", message).WithDetails(map[string]string{field: message})
+}
+
+// lookupError turns a repo's not found error into notFound and passes any
+// other failure through unchanged.
+func lookupError(err error, notFound error) error {
+	if errors.Is(err, ErrNotFound) {
+		return notFound
+	}
+
+	return err
+}
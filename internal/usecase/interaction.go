@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -9,38 +10,74 @@ import (
 	"social/api/internal/repo"
 )
 
+var (
+	ErrFollowSelf       = NewError(ErrValidation, "follow_self", "you cannot follow yourself")
+	ErrAlreadyLiked     = NewError(ErrConflict, "already_liked", "post is already liked")
+	ErrLikeNotFound     = NewError(ErrNotFound, "like_not_found", "post is not liked")
+	ErrAlreadyFollowing = NewError(ErrConflict, "already_following", "user is already followed")
+	ErrFollowNotFound   = NewError(ErrNotFound, "follow_not_found", "user is not followed")
+	ErrLikesPrivate     = NewError(ErrForbidden, "likes_private", "this user's likes are private")
+)
+
 type interactionService struct {
-	likeRepo   repo.Like
-	followRepo repo.Follow
-	userRepo   repo.User
+	likeRepo     repo.Like
+	reactionRepo repo.Reaction
+	followRepo   repo.Follow
+	userRepo     repo.User
+	postRepo     repo.Post
+	timelines    *Timelines
+	views        postViews
+	gate         verifiedGate
 }
 
-func NewInteractionUseCase(likeRepo repo.Like, followRepo repo.Follow, userRepo repo.User) Interaction {
+func NewInteractionUseCase(likeRepo repo.Like, reactionRepo repo.Reaction, followRepo repo.Follow, userRepo repo.User, postRepo repo.Post, timelines *Timelines, unverified UnverifiedAccess) Interaction {
 	return &interactionService{
-		likeRepo:   likeRepo,
-		followRepo: followRepo,
-		userRepo:   userRepo,
+		likeRepo:     likeRepo,
+		reactionRepo: reactionRepo,
+		followRepo:   followRepo,
+		userRepo:     userRepo,
+		postRepo:     postRepo,
+		timelines:    timelines,
+		views:        postViews{postRepo: postRepo, userRepo: userRepo, reactionRepo: reactionRepo},
+		gate:         verifiedGate{userRepo: userRepo, access: unverified},
 	}
 }
 
+// LikePost adds a 👍 reaction, which is what likes are stored as.
 func (s *interactionService) LikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	like := &entity.Like{
-		UserID: userID,
-		PostID: postID,
+	err := s.gate.canWrite(ctx, userID)
+	if err != nil {
+		return err
 	}
 
-	err := s.likeRepo.Create(ctx, like)
+	_, err = s.postRepo.GetByID(ctx, postID)
 	if err != nil {
-		return fmt.Errorf("failed to like post: %w", err)
+		return lookupError(err, ErrPostNotFound)
+	}
+
+	like := &entity.Reaction{
+		UserID:     userID,
+		TargetType: entity.ReactionTargetPost,
+		TargetID:   postID,
+		Emoji:      entity.LikeEmoji,
+	}
+
+	err = s.reactionRepo.Create(ctx, like)
+	if errors.Is(err, ErrConflict) {
+		return ErrAlreadyLiked
+	}
+	if err != nil {
+		// The post may have been deleted since it was looked up
+		return lookupError(err, ErrPostNotFound)
 	}
 
 	return nil
 }
 
 func (s *interactionService) UnlikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	err := s.likeRepo.Delete(ctx, userID, postID)
+	err := s.reactionRepo.Delete(ctx, userID, entity.ReactionTargetPost, postID, entity.LikeEmoji)
 	if err != nil {
-		return fmt.Errorf("failed to unlike post: %w", err)
+		return lookupError(err, ErrLikeNotFound)
 	}
 
 	return nil
@@ -49,7 +86,12 @@ func (s *interactionService) UnlikePost(ctx context.Context, postID, userID uuid
 func (s *interactionService) FollowUser(ctx context.Context, userID, followerID uuid.UUID) error {
 	// Prevent users from following themselves
 	if userID == followerID {
-		return fmt.Errorf("you cannot follow yourself")
+		return ErrFollowSelf
+	}
+
+	err := s.gate.canWrite(ctx, followerID)
+	if err != nil {
+		return err
 	}
 
 	follow := &entity.Follow{
@@ -57,32 +99,44 @@ func (s *interactionService) FollowUser(ctx context.Context, userID, followerID
 		FollowerID: followerID,
 	}
 
-	err := s.followRepo.Create(ctx, follow)
+	err = s.followRepo.Create(ctx, follow)
+	if errors.Is(err, ErrConflict) {
+		return ErrAlreadyFollowing
+	}
 	if err != nil {
 		return fmt.Errorf("failed to follow user: %w", err)
 	}
 
+	s.timelines.Followed(ctx, userID, followerID)
+
 	return nil
 }
 
 func (s *interactionService) UnfollowUser(ctx context.Context, userID, followerID uuid.UUID) error {
 	err := s.followRepo.Delete(ctx, userID, followerID)
 	if err != nil {
-		return fmt.Errorf("failed to unfollow user: %w", err)
+		return lookupError(err, ErrFollowNotFound)
 	}
 
+	s.timelines.Unfollowed(ctx, userID, followerID)
+
 	return nil
 }
 
-func (s *interactionService) GetFollowers(ctx context.Context, username string) ([]entity.User, error) {
+func (s *interactionService) GetFollowers(ctx context.Context, username string, limit int, cursor string) ([]entity.User, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, "", lookupError(err, ErrUserNotFound)
 	}
 
-	followers, err := s.followRepo.GetFollowers(ctx, user.ID)
+	followers, next, err := s.followRepo.GetFollowers(ctx, user.ID, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get followers: %w", err)
+		return nil, "", fmt.Errorf("failed to get followers: %w", err)
 	}
 
 	// Clear passwords before returning
@@ -90,18 +144,23 @@ func (s *interactionService) GetFollowers(ctx context.Context, username string)
 		followers[i].Password = ""
 	}
 
-	return followers, nil
+	return followers, encodeCursor(next), nil
 }
 
-func (s *interactionService) GetFollowing(ctx context.Context, username string) ([]entity.User, error) {
+func (s *interactionService) GetFollowing(ctx context.Context, username string, limit int, cursor string) ([]entity.User, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, "", lookupError(err, ErrUserNotFound)
 	}
 
-	following, err := s.followRepo.GetFollowing(ctx, user.ID)
+	following, next, err := s.followRepo.GetFollowing(ctx, user.ID, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get following: %w", err)
+		return nil, "", fmt.Errorf("failed to get following: %w", err)
 	}
 
 	// Clear passwords before returning
@@ -109,5 +168,56 @@ func (s *interactionService) GetFollowing(ctx context.Context, username string)
 		following[i].Password = ""
 	}
 
-	return following, nil
-}
\ No newline at end of file
+	return following, encodeCursor(next), nil
+}
+
+// GetPostLikers pages through the users who liked a post. Users who keep
+// their likes private are only listed to themselves.
+func (s *interactionService) GetPostLikers(ctx context.Context, postID, viewerID uuid.UUID, limit int, cursor string) ([]entity.Liker, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	_, err = s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, "", lookupError(err, ErrPostNotFound)
+	}
+
+	likers, next, err := s.likeRepo.GetLikers(ctx, postID, viewerID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get likers: %w", err)
+	}
+
+	return likers, encodeCursor(next), nil
+}
+
+// GetLikedPosts pages through the posts a user liked. Private likes are only
+// shown to the user.
+func (s *interactionService) GetLikedPosts(ctx context.Context, username string, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, "", lookupError(err, ErrUserNotFound)
+	}
+
+	if user.PrivateLikes && user.ID != viewerID {
+		return nil, "", ErrLikesPrivate
+	}
+
+	posts, next, err := s.likeRepo.GetLikedPosts(ctx, user.ID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get liked posts: %w", err)
+	}
+
+	err = s.views.fill(ctx, viewerID, postRefs(posts)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, encodeCursor(next), nil
+}
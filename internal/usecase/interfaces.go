@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"social/api/internal/entity"
@@ -9,25 +10,82 @@ import (
 
 type User interface {
 	Register(ctx context.Context, name, username, email, password string) (*entity.User, error)
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string, client entity.Client) (*entity.LoginResult, error)
+	LoginMFA(ctx context.Context, mfaToken, code string, client entity.Client) (*entity.User, *entity.AuthTokens, error)
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error
 	GetProfile(ctx context.Context, username string) (*entity.User, error)
-	UpdateProfile(ctx context.Context, userID uuid.UUID, name, bio *string, imageURL *string) (*entity.User, error)
+	GetProfileByID(ctx context.Context, userID uuid.UUID) (*entity.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, name, bio *string, imageURL *string, privateLikes *bool) (*entity.User, error)
 	SearchUsers(ctx context.Context, query string) ([]entity.User, error)
+	ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string, client entity.Client) error
+	ChangeEmail(ctx context.Context, userID uuid.UUID, password, email string, client entity.Client) (*entity.User, error)
+	ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (*entity.User, error)
+	FindRenamedUser(ctx context.Context, username string) (*entity.User, error)
+	SetRoles(ctx context.Context, username string, roles []string) (*entity.User, error)
+}
+
+type Password interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type Verification interface {
+	SendVerification(ctx context.Context, user *entity.User) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+type OIDC interface {
+	Start(ctx context.Context, provider string) (string, string, error)
+	Callback(ctx context.Context, provider, code, state, sealedState string, client entity.Client) (*entity.LoginResult, error)
+}
+
+type Session interface {
+	Start(ctx context.Context, user *entity.User, client entity.Client) (*entity.AuthTokens, error)
+	Authenticate(ctx context.Context, sessionID, userID uuid.UUID) error
+	Refresh(ctx context.Context, refreshToken string) (*entity.AuthTokens, error)
+	Logout(ctx context.Context, sessionID, userID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.Session, error)
+	RevokeSession(ctx context.Context, sessionID, userID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID, currentID uuid.UUID) error
+}
+
+type Token interface {
+	CreateToken(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*entity.Token, string, error)
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]entity.Token, error)
+	RevokeToken(ctx context.Context, tokenID, userID uuid.UUID) error
+	Authenticate(ctx context.Context, token string) (*entity.User, *entity.Token, error)
 }
 
 type Post interface {
-	CreatePost(ctx context.Context, authorID uuid.UUID, content string, imageURL *string) (*entity.Post, error)
-	GetPostByID(ctx context.Context, postID uuid.UUID) (*entity.Post, error)
-	GetPostsByUser(ctx context.Context, username string) ([]entity.Post, error)
+	CreatePost(ctx context.Context, authorID uuid.UUID, content string, imageURL *string, quoteOfID *uuid.UUID) (*entity.Post, error)
+	GetPostByID(ctx context.Context, postID, viewerID uuid.UUID) (*entity.Post, error)
+	GetPostsByUser(ctx context.Context, username string, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error)
 	UpdatePost(ctx context.Context, postID, userID uuid.UUID, content string, imageURL *string) (*entity.Post, error)
 	DeletePost(ctx context.Context, postID, userID uuid.UUID) error
-	GetFeed(ctx context.Context, userID uuid.UUID) ([]entity.Post, error)
+	Repost(ctx context.Context, postID, userID uuid.UUID) (*entity.Post, error)
+	Unrepost(ctx context.Context, postID, userID uuid.UUID) error
+	GetFeed(ctx context.Context, userID uuid.UUID, limit int, cursor string) ([]entity.FeedItem, string, error)
+	GetForYouFeed(ctx context.Context, userID uuid.UUID, limit int, cursor string) ([]entity.RankedFeedItem, string, error)
+	GetExplore(ctx context.Context, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error)
+	GetTrending(ctx context.Context, viewerID uuid.UUID, limit int) ([]entity.Post, error)
+}
+
+// FeedRanker orders feed items for a viewer, best first, as they stood at
+// asOf. The same items ranked as of the same time come in the same order.
+type FeedRanker interface {
+	Rank(ctx context.Context, viewerID uuid.UUID, items []entity.FeedItem, asOf time.Time) ([]entity.RankedFeedItem, error)
 }
 
 type Comment interface {
-	AddComment(ctx context.Context, postID, userID uuid.UUID, content string) (*entity.Comment, error)
-	GetComments(ctx context.Context, postID uuid.UUID) ([]entity.Comment, error)
-	DeleteComment(ctx context.Context, commentID, userID uuid.UUID) error
+	AddComment(ctx context.Context, postID, userID uuid.UUID, content string, parentID *uuid.UUID) (*entity.Comment, error)
+	GetComments(ctx context.Context, postID uuid.UUID, limit int, cursor string) ([]entity.Comment, string, error)
+	GetThread(ctx context.Context, postID, commentID uuid.UUID, limit int, cursor string) ([]entity.Comment, string, error)
+	EditComment(ctx context.Context, postID, commentID, userID uuid.UUID, content string) (*entity.Comment, error)
+	GetCommentRevisions(ctx context.Context, postID, commentID, userID uuid.UUID) ([]entity.CommentRevision, error)
+	DeleteComment(ctx context.Context, postID, commentID, userID uuid.UUID) error
 }
 
 type Interaction interface {
@@ -35,6 +93,14 @@ type Interaction interface {
 	UnlikePost(ctx context.Context, postID, userID uuid.UUID) error
 	FollowUser(ctx context.Context, userID, followerID uuid.UUID) error
 	UnfollowUser(ctx context.Context, userID, followerID uuid.UUID) error
-	GetFollowers(ctx context.Context, username string) ([]entity.User, error)
-	GetFollowing(ctx context.Context, username string) ([]entity.User, error)
-}
\ No newline at end of file
+	GetFollowers(ctx context.Context, username string, limit int, cursor string) ([]entity.User, string, error)
+	GetFollowing(ctx context.Context, username string, limit int, cursor string) ([]entity.User, string, error)
+	GetPostLikers(ctx context.Context, postID, viewerID uuid.UUID, limit int, cursor string) ([]entity.Liker, string, error)
+	GetLikedPosts(ctx context.Context, username string, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error)
+}
+
+type Reaction interface {
+	AddReaction(ctx context.Context, userID uuid.UUID, target entity.ReactionTarget, emoji string) error
+	RemoveReaction(ctx context.Context, userID uuid.UUID, target entity.ReactionTarget, emoji string) error
+	GetReactionSummary(ctx context.Context, viewerID uuid.UUID, target entity.ReactionTarget) (*entity.ReactionSummary, error)
+}
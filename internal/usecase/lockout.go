@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+const (
+	attemptScopeEmail = "email"
+	attemptScopeIP    = "ip"
+)
+
+// LockoutPolicy configures how failed logins are throttled. Once a key reaches
+// its threshold within Window, every further failure doubles the lockout,
+// starting at BaseLockout and capped at MaxLockout.
+type LockoutPolicy struct {
+	MaxAttempts      int
+	MaxAttemptsPerIP int
+	Window           time.Duration
+	BaseLockout      time.Duration
+	MaxLockout       time.Duration
+}
+
+// LockoutError is returned while an email or IP address is locked out.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// LoginGuard tracks failed logins per email and per IP address.
+type LoginGuard struct {
+	attemptRepo repo.LoginAttempt
+	auditRepo   repo.Audit
+	policy      LockoutPolicy
+}
+
+func NewLoginGuard(attemptRepo repo.LoginAttempt, auditRepo repo.Audit, policy LockoutPolicy) *LoginGuard {
+	return &LoginGuard{
+		attemptRepo: attemptRepo,
+		auditRepo:   auditRepo,
+		policy:      policy,
+	}
+}
+
+// Check returns a LockoutError if either the email or the IP is locked.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	var retryAfter time.Duration
+	for scope, key := range g.keys(email, ip) {
+		attempt, err := g.attemptRepo.Get(ctx, scope, key)
+		if err != nil {
+			return fmt.Errorf("failed to check login attempts: %w", err)
+		}
+
+		if attempt != nil && attempt.LockedUntil != nil {
+			retryAfter = max(retryAfter, time.Until(*attempt.LockedUntil))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// Fail records a failed attempt and returns a LockoutError if it tripped a lock.
+func (g *LoginGuard) Fail(ctx context.Context, userID *uuid.UUID, email, ip string) error {
+	var retryAfter time.Duration
+	for scope, key := range g.keys(email, ip) {
+		failures, err := g.attemptRepo.RecordFailure(ctx, scope, key, g.policy.Window)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+
+		threshold := g.policy.MaxAttempts
+		if scope == attemptScopeIP {
+			threshold = g.policy.MaxAttemptsPerIP
+		}
+		if failures < threshold {
+			continue
+		}
+
+		lockout := g.lockoutFor(failures - threshold)
+		err = g.attemptRepo.Lock(ctx, scope, key, time.Now().Add(lockout))
+		if err != nil {
+			return fmt.Errorf("failed to lock login: %w", err)
+		}
+
+		err = g.auditRepo.Create(ctx, &entity.AuditEvent{
+			UserID:    userID,
+			Event:     entity.AuditLoginLocked,
+			Email:     normalizeEmail(email),
+			IPAddress: ip,
+			Details:   fmt.Sprintf("%s locked for %s after %d failed attempts", scope, lockout, failures),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write audit event: %w", err)
+		}
+
+		retryAfter = max(retryAfter, lockout)
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// Succeed clears the failure counter of the email after a successful login.
+// The IP counter is left to age out of the window, so an attacker who owns
+// one account cannot use its logins to keep spraying others from that IP.
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	err := g.attemptRepo.Reset(ctx, attemptScopeEmail, normalizeEmail(email))
+	if err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+func (g *LoginGuard) lockoutFor(excess int) time.Duration {
+	lockout := g.policy.BaseLockout
+	for range excess {
+		lockout *= 2
+		if lockout >= g.policy.MaxLockout {
+			return g.policy.MaxLockout
+		}
+	}
+
+	return min(lockout, g.policy.MaxLockout)
+}
+
+func (g *LoginGuard) keys(email, ip string) map[string]string {
+	keys := map[string]string{attemptScopeEmail: normalizeEmail(email)}
+	if ip != "" {
+		keys[attemptScopeIP] = ip
+	}
+
+	return keys
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
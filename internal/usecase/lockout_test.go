@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"social/api/internal/entity"
+)
+
+// memoryAttempts keeps login attempts in memory and never forgets failures.
+type memoryAttempts struct {
+	attempts map[string]*entity.LoginAttempt
+}
+
+func newMemoryAttempts() *memoryAttempts {
+	return &memoryAttempts{attempts: map[string]*entity.LoginAttempt{}}
+}
+
+func (m *memoryAttempts) Get(_ context.Context, scope, key string) (*entity.LoginAttempt, error) {
+	return m.attempts[scope+":"+key], nil
+}
+
+func (m *memoryAttempts) RecordFailure(_ context.Context, scope, key string, _ time.Duration) (int, error) {
+	attempt, ok := m.attempts[scope+":"+key]
+	if !ok {
+		attempt = &entity.LoginAttempt{Scope: scope, Key: key}
+		m.attempts[scope+":"+key] = attempt
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = time.Now()
+
+	return attempt.Failures, nil
+}
+
+func (m *memoryAttempts) Lock(_ context.Context, scope, key string, until time.Time) error {
+	m.attempts[scope+":"+key].LockedUntil = &until
+	return nil
+}
+
+func (m *memoryAttempts) Reset(_ context.Context, scope, key string) error {
+	delete(m.attempts, scope+":"+key)
+	return nil
+}
+
+type memoryAudit struct {
+	events []entity.AuditEvent
+}
+
+func (m *memoryAudit) Create(_ context.Context, event *entity.AuditEvent) error {
+	m.events = append(m.events, *event)
+	return nil
+}
+
+var testLockoutPolicy = LockoutPolicy{
+	MaxAttempts:      3,
+	MaxAttemptsPerIP: 10,
+	Window:           15 * time.Minute,
+	BaseLockout:      30 * time.Second,
+	MaxLockout:       5 * time.Minute,
+}
+
+func TestLoginGuardBackoff(t *testing.T) {
+	tests := []struct {
+		failures    int
+		wantLockout time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{3, 30 * time.Second},
+		{4, time.Minute},
+		{5, 2 * time.Minute},
+		{6, 4 * time.Minute},
+		{7, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d failures", tt.failures), func(t *testing.T) {
+			ctx := context.Background()
+			audit := &memoryAudit{}
+			guard := NewLoginGuard(newMemoryAttempts(), audit, testLockoutPolicy)
+
+			var err error
+			for range tt.failures {
+				err = guard.Fail(ctx, nil, "alice@example.com", "")
+			}
+
+			var lockout *LockoutError
+			if tt.wantLockout == 0 {
+				if err != nil {
+					t.Fatalf("Fail() error = %v, want none below the threshold", err)
+				}
+				return
+			}
+			if !errors.As(err, &lockout) {
+				t.Fatalf("Fail() error = %v, want a LockoutError", err)
+			}
+			if lockout.RetryAfter != tt.wantLockout {
+				t.Errorf("RetryAfter = %s, want %s", lockout.RetryAfter, tt.wantLockout)
+			}
+			if !errors.Is(err, ErrRateLimited) {
+				t.Errorf("Fail() error = %v, want it to be ErrRateLimited", err)
+			}
+			if len(audit.events) != tt.failures-testLockoutPolicy.MaxAttempts+1 {
+				t.Errorf("got %d audit events, want one per lock", len(audit.events))
+			}
+
+			err = guard.Check(ctx, "Alice@Example.com ", "")
+			if !errors.As(err, &lockout) {
+				t.Errorf("Check() error = %v, want the normalized email to be locked", err)
+			}
+		})
+	}
+}
+
+func TestLoginGuardSucceed(t *testing.T) {
+	ctx := context.Background()
+	attempts := newMemoryAttempts()
+	guard := NewLoginGuard(attempts, &memoryAudit{}, testLockoutPolicy)
+
+	for _, email := range []string{"alice@example.com", "alice@example.com", "bob@example.com"} {
+		err := guard.Fail(ctx, nil, email, "192.0.2.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := guard.Succeed(ctx, "ALICE@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scope, key   string
+		wantFailures int
+	}{
+		{attemptScopeEmail, "alice@example.com", 0},
+		{attemptScopeEmail, "bob@example.com", 1},
+		{attemptScopeIP, "192.0.2.1", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope+" "+tt.key, func(t *testing.T) {
+			attempt, _ := attempts.Get(ctx, tt.scope, tt.key)
+			failures := 0
+			if attempt != nil {
+				failures = attempt.Failures
+			}
+			if failures != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", failures, tt.wantFailures)
+			}
+		})
+	}
+}
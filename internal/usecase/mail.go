@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"social/api/pkg/mailer"
+)
+
+// sendInBackground delivers msg without holding up the request. Failures are
+// only logged; every email we send can be requested again by the user.
+func sendInBackground(ctx context.Context, m mailer.Mailer, msg mailer.Message) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := m.Send(ctx, msg); err != nil {
+			log.Printf("failed to send email %q: %v", msg.Subject, err)
+		}
+	}()
+}
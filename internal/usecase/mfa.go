@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/pkg/jwt"
+	"social/api/pkg/totp"
+)
+
+const (
+	mfaChallengePurpose = "mfa"
+	mfaChallengeTTL     = 5 * time.Minute
+	recoveryCodeCount   = 10
+)
+
+var (
+	ErrInvalidMFACode       = NewError(ErrUnauthorized, "invalid_mfa_code", "invalid two-factor code")
+	ErrInvalidMFAChallenge  = NewError(ErrUnauthorized, "invalid_mfa_challenge", "invalid or expired two-factor challenge")
+	ErrMFAAlreadyEnabled    = NewError(ErrConflict, "mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFANotEnabled        = NewError(ErrConflict, "mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMFAEnrollmentMissing = NewError(ErrConflict, "mfa_enrollment_missing", "start two-factor enrollment first")
+)
+
+func (s *userService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	current, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if current != nil && current.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	sealed, err := s.secrets.Seal([]byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	err = s.mfaRepo.SaveTOTP(ctx, &entity.TOTP{UserID: userID, Secret: sealed})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save secret: %w", err)
+	}
+
+	return &entity.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.URI(secret, s.totpIssuer, user.Email),
+	}, nil
+}
+
+func (s *userService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	current, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if current == nil {
+		return nil, ErrMFAEnrollmentMissing
+	}
+	if current.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	err = s.verifyTOTP(ctx, current, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	err = s.mfaRepo.EnableTOTP(ctx, userID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (s *userService) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	current, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if current == nil || current.EnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	err = s.verifyMFACode(ctx, current, code)
+	if err != nil {
+		return err
+	}
+
+	err = s.mfaRepo.DeleteTOTP(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userService) LoginMFA(ctx context.Context, mfaToken, code string, client entity.Client) (*entity.User, *entity.AuthTokens, error) {
+	claims, err := s.tokens.Parse(mfaToken)
+	if err != nil || claims.Purpose != mfaChallengePurpose {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	err = s.guard.Check(ctx, user.Email, client.IPAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if current == nil || current.EnabledAt == nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	err = s.verifyMFACode(ctx, current, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if lockErr := s.guard.Fail(ctx, &user.ID, user.Email, client.IPAddress); lockErr != nil {
+			return nil, nil, lockErr
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.guard.Succeed(ctx, user.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Clear password before returning
+	user.Password = ""
+	return user, tokens, nil
+}
+
+// startLogin opens a session for an authenticated user, or hands out a
+// challenge for LoginMFA if the account has two-factor authentication enabled.
+func startLogin(ctx context.Context, mfaRepo repo.MFA, sessions Session, tokens *jwt.Manager, user *entity.User, client entity.Client) (*entity.LoginResult, error) {
+	mfa, err := mfaRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+
+	if mfa != nil && mfa.EnabledAt != nil {
+		challenge, expiresAt, err := mfaChallenge(tokens, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate two-factor challenge: %w", err)
+		}
+
+		return &entity.LoginResult{User: user, MFAToken: challenge, MFAExpiresAt: expiresAt}, nil
+	}
+
+	authTokens, err := sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResult{User: user, Tokens: authTokens}, nil
+}
+
+// mfaChallenge returns a short-lived token that only LoginMFA accepts.
+func mfaChallenge(tokens *jwt.Manager, user *entity.User) (string, time.Time, error) {
+	return tokens.IssueWithTTL(jwt.Claims{
+		Subject:  user.ID.String(),
+		Username: user.Username,
+		Purpose:  mfaChallengePurpose,
+	}, mfaChallengeTTL)
+}
+
+// verifyMFACode accepts either a current TOTP code or an unused recovery code.
+func (s *userService) verifyMFACode(ctx context.Context, current *entity.TOTP, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		return s.verifyTOTP(ctx, current, code)
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, current.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (s *userService) verifyTOTP(ctx context.Context, current *entity.TOTP, code string) error {
+	secret, err := s.secrets.Open(current.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	step, ok := totp.Validate(strings.TrimSpace(code), string(secret), time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := s.mfaRepo.UseTOTPStep(ctx, current.UserID, step)
+	if err != nil {
+		return fmt.Errorf("failed to check two-factor code: %w", err)
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/pkg/jwt"
+	"social/api/pkg/oidc"
+	"social/api/pkg/secretbox"
+)
+
+const (
+	oidcStateTTL        = 10 * time.Minute
+	maxUsernameAttempts = 5
+)
+
+var (
+	ErrUnknownOIDCProvider    = NewError(ErrNotFound, "unknown_provider", "unknown login provider")
+	ErrInvalidOIDCState       = NewError(ErrValidation, "invalid_login_state", "invalid or expired login state")
+	ErrOIDCLoginFailed        = NewError(ErrUnauthorized, "provider_login_failed", "login with provider failed")
+	ErrOIDCEmailMissing       = NewError(ErrValidation, "provider_email_missing", "login provider did not share an email address")
+	ErrOIDCAccountNotLinkable = NewError(ErrConflict, "account_not_linkable", "an account with this email already exists; both addresses must be verified to link it")
+)
+
+// oidcState travels in an encrypted cookie from Start to Callback.
+type oidcState struct {
+	Provider  string `json:"provider"`
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type oidcService struct {
+	providers    map[string]*oidc.Provider
+	userRepo     repo.User
+	identityRepo repo.Identity
+	mfaRepo      repo.MFA
+	sessions     Session
+	verification Verification
+	tokens       *jwt.Manager
+	secrets      *secretbox.Box
+}
+
+func NewOIDCUseCase(providers map[string]*oidc.Provider, userRepo repo.User, identityRepo repo.Identity, mfaRepo repo.MFA, sessions Session, verification Verification, tokens *jwt.Manager, secrets *secretbox.Box) OIDC {
+	return &oidcService{
+		providers:    providers,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		mfaRepo:      mfaRepo,
+		sessions:     sessions,
+		verification: verification,
+		tokens:       tokens,
+		secrets:      secrets,
+	}
+}
+
+// Start returns the provider URL to send the user to and the sealed state the
+// caller has to hand back to Callback.
+func (s *oidcService) Start(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownOIDCProvider
+	}
+
+	state := oidcState{
+		Provider:  providerName,
+		ExpiresAt: time.Now().Add(oidcStateTTL).Unix(),
+	}
+
+	var err error
+	for _, v := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		*v, err = oidc.RandomString()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate login state: %w", err)
+		}
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state.State, state.Nonce, state.Verifier)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrOIDCLoginFailed, err)
+	}
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode login state: %w", err)
+	}
+
+	sealed, err := s.secrets.Seal(payload)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt login state: %w", err)
+	}
+
+	return authURL, sealed, nil
+}
+
+// Callback finishes the login: it redeems the code, finds or creates the
+// linked user and then logs in like a password login would.
+func (s *oidcService) Callback(ctx context.Context, providerName, code, state, sealedState string, client entity.Client) (*entity.LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	saved, err := s.openState(sealedState)
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Provider != providerName || subtle.ConstantTimeCompare([]byte(saved.State), []byte(state)) != 1 {
+		return nil, ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, code, saved.Verifier, saved.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOIDCLoginFailed, err)
+	}
+
+	user, err := s.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear password before returning
+	user.Password = ""
+
+	return startLogin(ctx, s.mfaRepo, s.sessions, s.tokens, user, client)
+}
+
+func (s *oidcService) openState(sealedState string) (*oidcState, error) {
+	payload, err := s.secrets.Open(sealedState)
+	if err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+
+	var state oidcState
+	err = json.Unmarshal(payload, &state)
+	if err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+
+	if time.Now().After(time.Unix(state.ExpiresAt, 0)) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	return &state, nil
+}
+
+// resolveUser returns the user linked to the provider identity. Unknown
+// identities are linked to an existing account with the same email, or get a
+// new account.
+func (s *oidcService) resolveUser(ctx context.Context, providerName string, claims *oidc.Claims) (*entity.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	if identity != nil {
+		err = s.identityRepo.TouchLastLogin(ctx, identity.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update identity: %w", err)
+		}
+
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, lookupError(err, ErrUserNotFound)
+		}
+
+		return user, nil
+	}
+
+	if claims.Email == "" {
+		return nil, ErrOIDCEmailMissing
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		user, err = s.createUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	case !claims.EmailVerified || user.EmailVerifiedAt == nil:
+		// Both sides must vouch for the address, or whoever registered it
+		// first could take over the other person's account.
+		return nil, ErrOIDCAccountNotLinkable
+	}
+
+	err = s.identityRepo.Create(ctx, &entity.Identity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return user, nil
+}
+
+// createUser registers a user without a usable password. They can set one
+// through the password reset flow.
+func (s *oidcService) createUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	username, err := s.uniqueUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = username
+	}
+
+	user := &entity.User{
+		Name:     name,
+		Username: username,
+		Email:    claims.Email,
+		Password: string(hashedPassword),
+	}
+
+	err = s.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if claims.EmailVerified {
+		_, err = s.userRepo.MarkEmailVerified(ctx, user.ID, user.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify email: %w", err)
+		}
+
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	} else {
+		err = s.verification.SendVerification(ctx, user)
+		if err != nil {
+			log.Printf("failed to send verification email: %v", err)
+		}
+	}
+
+	return user, nil
+}
+
+// uniqueUsername derives a free username from the provider's preferred
+// username or the email address, adding digits if it is taken.
+func (s *oidcService) uniqueUsername(ctx context.Context, claims *oidc.Claims) (string, error) {
+	base := usernameFrom(claims.PreferredUsername)
+	if base == "" {
+		local, _, _ := strings.Cut(claims.Email, "@")
+		base = usernameFrom(local)
+	}
+	if len(base) < minUsernameLength {
+		base = "user"
+	}
+
+	candidate := base
+	for range maxUsernameAttempts {
+		_, err := s.userRepo.GetByUsername(ctx, candidate)
+		if errors.Is(err, ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate username: %w", err)
+		}
+		// Leave room for the numeric suffix
+		candidate = fmt.Sprintf("%s%04d", base[:min(len(base), maxUsernameLength-4)], n.Int64())
+	}
+
+	return "", fmt.Errorf("failed to find a free username")
+}
+
+// usernameFrom keeps lowercase letters, digits and underscores.
+func usernameFrom(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+
+	username := b.String()
+	if len(username) > maxUsernameLength {
+		username = username[:maxUsernameLength]
+	}
+
+	return username
+}
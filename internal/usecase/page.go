@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"fmt"
+
+	"social/api/internal/entity"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageRequest checks the page size and cursor a client asked for. A zero limit
+// means the default page size.
+func pageRequest(limit int, cursor string) (entity.PageRequest, error) {
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit < 1 || limit > maxPageSize {
+		return entity.PageRequest{}, ValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", maxPageSize))
+	}
+
+	page := entity.PageRequest{Limit: limit}
+	if cursor != "" {
+		after, err := entity.ParseCursor(cursor)
+		if err != nil {
+			return entity.PageRequest{}, ValidationError("cursor", "invalid cursor")
+		}
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// encodeCursor returns the cursor of the next page, or "" on the last page.
+func encodeCursor(next *entity.Cursor) string {
+	if next == nil {
+		return ""
+	}
+
+	return next.Encode()
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/pkg/mailer"
+)
+
+const minPasswordLength = 6
+
+var (
+	ErrInvalidResetToken = NewError(ErrValidation, "invalid_reset_token", "invalid or expired reset token")
+	ErrPasswordTooShort  = NewError(ErrValidation, "password_too_short", fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+)
+
+type passwordService struct {
+	userRepo    repo.User
+	tokenRepo   repo.OneTimeToken
+	sessionRepo repo.Session
+	mailer      mailer.Mailer
+	resetURL    string
+	resetTTL    time.Duration
+}
+
+func NewPasswordUseCase(userRepo repo.User, tokenRepo repo.OneTimeToken, sessionRepo repo.Session, m mailer.Mailer, resetURL string, resetTTL time.Duration) Password {
+	return &passwordService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		mailer:      m,
+		resetURL:    resetURL,
+		resetTTL:    resetTTL,
+	}
+}
+
+// ForgotPassword emails a reset link if the address belongs to an account.
+// Unknown addresses are not an error, so callers cannot probe for accounts.
+func (s *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	// Only the most recent link is valid
+	err = s.tokenRepo.DeleteByUserID(ctx, user.ID, entity.PurposePasswordReset)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	err = s.tokenRepo.Create(ctx, &entity.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   entity.PurposePasswordReset,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Name, s.resetTTL, linkWithToken(s.resetURL, token)),
+	}
+
+	// Sending happens in the background so the response time does not tell
+	// registered addresses apart from unknown ones.
+	sendInBackground(ctx, s.mailer, msg)
+
+	return nil
+}
+
+// ResetPassword sets a new password and signs the user out everywhere.
+func (s *passwordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	err := validatePassword("password", newPassword)
+	if err != nil {
+		return err
+	}
+
+	resetToken, err := s.tokenRepo.Consume(ctx, hashToken(token), entity.PurposePasswordReset)
+	if err != nil {
+		return fmt.Errorf("failed to check reset token: %w", err)
+	}
+	if resetToken == nil {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.userRepo.UpdatePassword(ctx, resetToken.UserID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	err = s.sessionRepo.RevokeAllByUserID(ctx, resetToken.UserID, uuid.Nil)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	err = s.tokenRepo.DeleteByUserID(ctx, resetToken.UserID, entity.PurposePasswordReset)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+// linkWithToken appends the token as a query parameter to base.
+func linkWithToken(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -2,71 +2,147 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
 )
 
+// forYouWindow is how many of the newest home feed items the For You feed
+// ranks. Older items are left to the chronological feed.
+const forYouWindow = 200
+
+var (
+	ErrRepostNotFound    = NewError(ErrNotFound, "repost_not_found", "post is not reposted")
+	ErrRepostNotEditable = NewError(ErrForbidden, "repost_not_editable", "reposts cannot be edited")
+)
+
 type postService struct {
-	postRepo   repo.Post
-	userRepo   repo.User
+	postRepo     repo.Post
+	userRepo     repo.User
+	trendingRepo repo.Trending
+	timelines    *Timelines
+	ranker       FeedRanker
+	views        postViews
+	gate         verifiedGate
 }
 
-func NewPostUseCase(postRepo repo.Post, userRepo repo.User) Post {
+func NewPostUseCase(postRepo repo.Post, userRepo repo.User, reactionRepo repo.Reaction, trendingRepo repo.Trending, timelines *Timelines, ranker FeedRanker, unverified UnverifiedAccess) Post {
 	return &postService{
-		postRepo: postRepo,
-		userRepo: userRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		trendingRepo: trendingRepo,
+		timelines:    timelines,
+		ranker:       ranker,
+		views:        postViews{postRepo: postRepo, userRepo: userRepo, reactionRepo: reactionRepo},
+		gate:         verifiedGate{userRepo: userRepo, access: unverified},
 	}
 }
 
-func (s *postService) CreatePost(ctx context.Context, authorID uuid.UUID, content string, imageURL *string) (*entity.Post, error) {
+// CreatePost creates a post, which quotes quoteOfID unless that is nil.
+func (s *postService) CreatePost(ctx context.Context, authorID uuid.UUID, content string, imageURL *string, quoteOfID *uuid.UUID) (*entity.Post, error) {
+	err := validatePost(content, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.gate.canPost(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quoteOfID != nil {
+		original, err := s.original(ctx, *quoteOfID)
+		if err != nil {
+			return nil, err
+		}
+		quoteOfID = &original.ID
+	}
+
 	post := &entity.Post{
-		AuthorID: authorID,
-		Content:  content,
-		ImageURL: imageURL,
+		AuthorID:  authorID,
+		Content:   content,
+		ImageURL:  imageURL,
+		QuoteOfID: quoteOfID,
 	}
 
-	err := s.postRepo.Create(ctx, post)
+	err = s.postRepo.Create(ctx, post)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
+	s.timelines.PostCreated(ctx, post)
+
+	err = s.views.fill(ctx, authorID, post)
+	if err != nil {
+		return nil, err
+	}
+
 	return post, nil
 }
 
-func (s *postService) GetPostByID(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+func (s *postService) GetPostByID(ctx context.Context, postID, viewerID uuid.UUID) (*entity.Post, error) {
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
-		return nil, fmt.Errorf("post not found: %w", err)
+		return nil, lookupError(err, ErrPostNotFound)
+	}
+
+	err = s.views.fill(ctx, viewerID, post)
+	if err != nil {
+		return nil, err
 	}
 
 	return post, nil
 }
 
-func (s *postService) GetPostsByUser(ctx context.Context, username string) ([]entity.Post, error) {
+func (s *postService) GetPostsByUser(ctx context.Context, username string, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, "", lookupError(err, ErrUserNotFound)
 	}
 
-	posts, err := s.postRepo.GetByAuthorID(ctx, user.ID)
+	posts, next, err := s.postRepo.GetByAuthorID(ctx, user.ID, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get posts: %w", err)
+		return nil, "", fmt.Errorf("failed to get posts: %w", err)
 	}
 
-	return posts, nil
+	err = s.views.fill(ctx, viewerID, postRefs(posts)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, encodeCursor(next), nil
 }
 
 func (s *postService) UpdatePost(ctx context.Context, postID, userID uuid.UUID, content string, imageURL *string) (*entity.Post, error) {
+	err := validatePost(content, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
-		return nil, fmt.Errorf("post not found: %w", err)
+		return nil, lookupError(err, ErrPostNotFound)
 	}
 
 	if post.AuthorID != userID {
-		return nil, fmt.Errorf("unauthorized: you can only update your own posts")
+		return nil, ErrPostForbidden
+	}
+	if post.RepostOfID != nil {
+		return nil, ErrRepostNotEditable
+	}
+
+	err = s.gate.canPost(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	post.Content = content
@@ -79,17 +155,28 @@ func (s *postService) UpdatePost(ctx context.Context, postID, userID uuid.UUID,
 		return nil, fmt.Errorf("failed to update post: %w", err)
 	}
 
+	err = s.views.fill(ctx, userID, post)
+	if err != nil {
+		return nil, err
+	}
+
 	return post, nil
 }
 
 func (s *postService) DeletePost(ctx context.Context, postID, userID uuid.UUID) error {
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
-		return fmt.Errorf("post not found: %w", err)
+		return lookupError(err, ErrPostNotFound)
 	}
 
 	if post.AuthorID != userID {
-		return fmt.Errorf("unauthorized: you can only delete your own posts")
+		moderator, err := isModerator(ctx, s.userRepo, userID)
+		if err != nil {
+			return err
+		}
+		if !moderator {
+			return ErrPostForbidden
+		}
 	}
 
 	err = s.postRepo.Delete(ctx, postID)
@@ -100,11 +187,192 @@ func (s *postService) DeletePost(ctx context.Context, postID, userID uuid.UUID)
 	return nil
 }
 
-func (s *postService) GetFeed(ctx context.Context, userID uuid.UUID) ([]entity.Post, error) {
-	posts, err := s.postRepo.GetFeed(ctx, userID)
+// Repost shares the post with the user's followers. Reposting a post twice
+// returns the first repost, and reposting a repost reposts its original.
+func (s *postService) Repost(ctx context.Context, postID, userID uuid.UUID) (*entity.Post, error) {
+	err := s.gate.canPost(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.original(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	repost := &entity.Post{
+		AuthorID:   userID,
+		RepostOfID: &original.ID,
+	}
+
+	err = s.postRepo.Create(ctx, repost)
+	if errors.Is(err, ErrConflict) {
+		repost, err = s.postRepo.GetRepost(ctx, userID, original.ID)
+	} else if err == nil {
+		s.timelines.PostCreated(ctx, repost)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to repost: %w", err)
+	}
+
+	err = s.views.fill(ctx, userID, repost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feed: %w", err)
+		return nil, err
+	}
+
+	return repost, nil
+}
+
+// Unrepost deletes the user's repost of the post. It also works once the post
+// itself is deleted.
+func (s *postService) Unrepost(ctx context.Context, postID, userID uuid.UUID) error {
+	repost, err := s.postRepo.GetRepost(ctx, userID, postID)
+	if err != nil {
+		return lookupError(err, ErrRepostNotFound)
+	}
+
+	err = s.postRepo.Delete(ctx, repost.ID)
+	if err != nil {
+		return lookupError(err, ErrRepostNotFound)
+	}
+
+	return nil
+}
+
+func (s *postService) GetFeed(ctx context.Context, userID uuid.UUID, limit int, cursor string) ([]entity.FeedItem, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, next, err := s.timelines.Read(ctx, userID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	err = s.views.fill(ctx, userID, feedPostRefs(items)...)
+	if err != nil {
+		return nil, "", err
+	}
+	showReposts(items)
+
+	return items, encodeCursor(next), nil
+}
+
+// GetForYouFeed ranks the newest forYouWindow items of the home feed and
+// pages through the ranking. The cursor keeps the time of the first page, so
+// later pages rank the same items the same way even as new posts arrive.
+func (s *postService) GetForYouFeed(ctx context.Context, userID uuid.UUID, limit int, cursor string) ([]entity.RankedFeedItem, string, error) {
+	page, err := pageRequest(limit, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	at := entity.RankedCursor{AsOf: time.Now().Truncate(time.Microsecond)}
+	if cursor != "" {
+		parsed, err := entity.ParseRankedCursor(cursor)
+		if err != nil {
+			return nil, "", ValidationError("cursor", "invalid cursor")
+		}
+		at = *parsed
+	}
+
+	// Everything up to and including AsOf, which the keyset compares as less
+	window := entity.PageRequest{
+		Limit: forYouWindow,
+		After: &entity.Cursor{CreatedAt: at.AsOf, ID: uuid.Max},
+	}
+	items, _, err := s.timelines.Read(ctx, userID, window)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	err = s.views.fill(ctx, userID, feedPostRefs(items)...)
+	if err != nil {
+		return nil, "", err
+	}
+	showReposts(items)
+
+	ranked, err := s.ranker.Rank(ctx, userID, items, at.AsOf)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rank feed: %w", err)
+	}
+
+	if at.Offset >= len(ranked) {
+		return []entity.RankedFeedItem{}, "", nil
+	}
+	end := min(at.Offset+page.Limit, len(ranked))
+	next := ""
+	if end < len(ranked) {
+		next = entity.RankedCursor{AsOf: at.AsOf, Offset: end}.Encode()
+	}
+
+	return ranked[at.Offset:end], next, nil
+}
+
+// GetExplore returns the latest posts of everyone.
+func (s *postService) GetExplore(ctx context.Context, viewerID uuid.UUID, limit int, cursor string) ([]entity.Post, string, error) {
+	page, err := pageRequest(limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	posts, next, err := s.postRepo.GetRecent(ctx, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get recent posts: %w", err)
+	}
+
+	err = s.views.fill(ctx, viewerID, postRefs(posts)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, encodeCursor(next), nil
+}
+
+// GetTrending returns the trending posts as of their last refresh.
+func (s *postService) GetTrending(ctx context.Context, viewerID uuid.UUID, limit int) ([]entity.Post, error) {
+	page, err := pageRequest(limit, "")
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.trendingRepo.Get(ctx, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending posts: %w", err)
+	}
+
+	err = s.views.fill(ctx, viewerID, postRefs(posts)...)
+	if err != nil {
+		return nil, err
 	}
 
 	return posts, nil
-}
\ No newline at end of file
+}
+
+// original looks up the post to repost or quote. Reposts have no content of
+// their own, so their original is used in their place.
+func (s *postService) original(ctx context.Context, postID uuid.UUID) (*entity.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, lookupError(err, ErrPostNotFound)
+	}
+	if post.RepostOfID == nil {
+		return post, nil
+	}
+
+	post, err = s.postRepo.GetByID(ctx, *post.RepostOfID)
+	if err != nil {
+		return nil, lookupError(err, ErrPostNotFound)
+	}
+	return post, nil
+}
+
+func validatePost(content string, imageURL *string) error {
+	err := validateText("content", content, true, maxPostLength)
+	if err != nil {
+		return err
+	}
+
+	return validateImageURL("image_url", imageURL)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+// postViews fills in the parts of posts that are not stored with them. It
+// loads each part for all posts at once instead of once per post.
+type postViews struct {
+	postRepo     repo.Post
+	userRepo     repo.User
+	reactionRepo repo.Reaction
+}
+
+// fill sets the originals of reposts and quote posts, and for those and the
+// posts themselves the authors, reactions and whether the viewer liked or
+// reposted them. viewerID is uuid.Nil for anonymous requests.
+func (v postViews) fill(ctx context.Context, viewerID uuid.UUID, posts ...*entity.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	originals, err := v.fillOriginals(ctx, posts)
+	if err != nil {
+		return err
+	}
+	posts = slices.Concat(posts, originals)
+
+	postIDs := make([]uuid.UUID, len(posts))
+	authorIDs := make([]uuid.UUID, 0, len(posts))
+	seen := make(map[uuid.UUID]bool, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+		if !seen[post.AuthorID] {
+			seen[post.AuthorID] = true
+			authorIDs = append(authorIDs, post.AuthorID)
+		}
+	}
+
+	users, err := v.userRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get authors: %w", err)
+	}
+
+	// Only the public profile goes out with a post
+	authors := make(map[uuid.UUID]*entity.User, len(users))
+	for _, user := range users {
+		authors[user.ID] = &entity.User{
+			ID:       user.ID,
+			Name:     user.Name,
+			Username: user.Username,
+			ImageURL: user.ImageURL,
+		}
+	}
+
+	reactions, err := reactionSummaries(ctx, v.reactionRepo, viewerID, entity.ReactionTargetPost, postIDs)
+	if err != nil {
+		return err
+	}
+
+	reposted := map[uuid.UUID]bool{}
+	if viewerID != uuid.Nil {
+		reposted, err = v.postRepo.GetRepostedIDs(ctx, viewerID, postIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get reposts: %w", err)
+		}
+	}
+
+	for _, post := range posts {
+		post.Author = authors[post.AuthorID]
+		post.Reactions = reactions[post.ID]
+		post.LikedByMe = slices.Contains(post.Reactions.Mine, entity.LikeEmoji)
+		post.RepostedByMe = reposted[post.ID]
+	}
+
+	return nil
+}
+
+// fillOriginals loads the originals of reposts and quote posts and returns
+// them. Only one level is loaded: the original of a quoted quote post is left
+// for clients to fetch.
+func (v postViews) fillOriginals(ctx context.Context, posts []*entity.Post) ([]*entity.Post, error) {
+	var ids []uuid.UUID
+	for _, post := range posts {
+		if id := post.OriginalID(); id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	found, err := v.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get original posts: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*entity.Post, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+
+	originals := make([]*entity.Post, 0, len(found))
+	for _, post := range posts {
+		id := post.OriginalID()
+		if id == nil {
+			continue
+		}
+		original, ok := byID[*id]
+		if !ok {
+			post.OriginalDeleted = true
+			continue
+		}
+		// Each post gets its own copy, so filling one does not show in another
+		copied := *original
+		post.Original = &copied
+		originals = append(originals, &copied)
+	}
+
+	return originals, nil
+}
+
+func postRefs(posts []entity.Post) []*entity.Post {
+	refs := make([]*entity.Post, len(posts))
+	for i := range posts {
+		refs[i] = &posts[i]
+	}
+	return refs
+}
+
+// showReposts puts the reposted posts in place of the reposts of the items,
+// which must have been filled. A repost of a deleted post is kept as the
+// tombstone it is.
+func showReposts(items []entity.FeedItem) {
+	for i := range items {
+		if items[i].Type == entity.FeedItemRepost && items[i].Post.Original != nil {
+			items[i].Post = *items[i].Post.Original
+		}
+	}
+}
+
+func feedPostRefs(items []entity.FeedItem) []*entity.Post {
+	refs := make([]*entity.Post, len(items))
+	for i := range items {
+		refs[i] = &items[i].Post
+	}
+	return refs
+}
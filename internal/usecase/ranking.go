@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+// RankingWeights tunes the default For You scorer. A post scores
+//
+//	recency * (1 + Likes*ln(1+likes) + Comments*ln(1+comments) + Affinity*ln(1+interactions))
+//
+// where recency halves every HalfLife and interactions counts the viewer's
+// likes and comments on the author's posts within AffinityWindow. Every post
+// that directly follows another one by the same author is multiplied by
+// DiversityPenalty once more.
+type RankingWeights struct {
+	HalfLife         time.Duration
+	Likes            float64
+	Comments         float64
+	Affinity         float64
+	AffinityWindow   time.Duration
+	DiversityPenalty float64
+}
+
+var DefaultRankingWeights = RankingWeights{
+	HalfLife:         6 * time.Hour,
+	Likes:            1,
+	Comments:         2,
+	Affinity:         1.5,
+	AffinityWindow:   30 * 24 * time.Hour,
+	DiversityPenalty: 0.7,
+}
+
+type scoringRanker struct {
+	statsRepo repo.FeedStats
+	weights   RankingWeights
+}
+
+func NewFeedRanker(statsRepo repo.FeedStats, weights RankingWeights) FeedRanker {
+	return &scoringRanker{
+		statsRepo: statsRepo,
+		weights:   weights,
+	}
+}
+
+func (r *scoringRanker) Rank(ctx context.Context, viewerID uuid.UUID, items []entity.FeedItem, asOf time.Time) ([]entity.RankedFeedItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	postIDs := make([]uuid.UUID, len(items))
+	var authorIDs []uuid.UUID
+	for i, item := range items {
+		postIDs[i] = item.Post.ID
+		if !slices.Contains(authorIDs, item.Post.AuthorID) {
+			authorIDs = append(authorIDs, item.Post.AuthorID)
+		}
+	}
+
+	stats, err := r.statsRepo.GetPostStats(ctx, postIDs, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post stats: %w", err)
+	}
+
+	affinity, err := r.statsRepo.GetAffinity(ctx, viewerID, authorIDs, asOf.Add(-r.weights.AffinityWindow), asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affinity: %w", err)
+	}
+
+	ranked := make([]entity.RankedFeedItem, len(items))
+	for i, item := range items {
+		s := stats[item.Post.ID]
+		interactions := affinity[item.Post.AuthorID]
+		ageHours := max(asOf.Sub(item.CreatedAt).Hours(), 0)
+		recency := math.Exp2(-ageHours / r.weights.HalfLife.Hours())
+
+		ranked[i] = entity.RankedFeedItem{
+			FeedItem: item,
+			Score: recency * (1 +
+				r.weights.Likes*math.Log1p(float64(s.Likes)) +
+				r.weights.Comments*math.Log1p(float64(s.Comments)) +
+				r.weights.Affinity*math.Log1p(float64(interactions))),
+			Features: map[string]float64{
+				"age_hours": ageHours,
+				"recency":   recency,
+				"likes":     float64(s.Likes),
+				"comments":  float64(s.Comments),
+				"affinity":  float64(interactions),
+				"diversity": 1,
+			},
+		}
+	}
+
+	return r.diversify(ranked), nil
+}
+
+// diversify orders the items by score, picking one at a time so that a post
+// right after another one by the same author only counts with the penalty.
+func (r *scoringRanker) diversify(items []entity.RankedFeedItem) []entity.RankedFeedItem {
+	ordered := make([]entity.RankedFeedItem, 0, len(items))
+	var lastAuthor uuid.UUID
+	streak := 0
+
+	for len(items) > 0 {
+		best, bestScore := 0, math.Inf(-1)
+		for i, item := range items {
+			score := item.Score
+			if item.Post.AuthorID == lastAuthor {
+				score *= math.Pow(r.weights.DiversityPenalty, float64(streak))
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		item := items[best]
+		items = slices.Delete(items, best, best+1)
+
+		if item.Post.AuthorID == lastAuthor {
+			streak++
+		} else {
+			lastAuthor, streak = item.Post.AuthorID, 1
+		}
+		if streak > 1 {
+			item.Features["diversity"] = math.Pow(r.weights.DiversityPenalty, float64(streak-1))
+			item.Score = bestScore
+		}
+
+		ordered = append(ordered, item)
+	}
+
+	return ordered
+}
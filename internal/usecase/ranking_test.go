@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+)
+
+func TestDiversify(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	type post struct {
+		name   string
+		author uuid.UUID
+		score  float64
+	}
+
+	tests := []struct {
+		name       string
+		posts      []post
+		wantOrder  []string
+		wantScores []float64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "different authors by score",
+			posts:      []post{{"b1", bob, 2}, {"a1", alice, 3}},
+			wantOrder:  []string{"a1", "b1"},
+			wantScores: []float64{3, 2},
+		},
+		{
+			name:       "penalty lets another author in between",
+			posts:      []post{{"a1", alice, 10}, {"a2", alice, 9}, {"b1", bob, 8}},
+			wantOrder:  []string{"a1", "b1", "a2"},
+			wantScores: []float64{10, 8, 9},
+		},
+		{
+			name:       "penalty grows with the streak",
+			posts:      []post{{"a1", alice, 10}, {"a2", alice, 9}, {"a3", alice, 8}, {"b1", bob, 5}},
+			wantOrder:  []string{"a1", "a2", "b1", "a3"},
+			wantScores: []float64{10, 6.3, 5, 8},
+		},
+		{
+			name:       "single author",
+			posts:      []post{{"a3", alice, 1}, {"a1", alice, 10}, {"a2", alice, 5}},
+			wantOrder:  []string{"a1", "a2", "a3"},
+			wantScores: []float64{10, 3.5, 0.49},
+		},
+		{
+			name:       "ties keep feed order",
+			posts:      []post{{"a1", alice, 1}, {"b1", bob, 1}},
+			wantOrder:  []string{"a1", "b1"},
+			wantScores: []float64{1, 1},
+		},
+	}
+
+	r := &scoringRanker{weights: RankingWeights{DiversityPenalty: 0.7}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := map[uuid.UUID]string{}
+			scores := map[uuid.UUID]float64{}
+			items := make([]entity.RankedFeedItem, len(tt.posts))
+			for i, p := range tt.posts {
+				id := uuid.New()
+				names[id] = p.name
+				scores[id] = p.score
+				items[i] = entity.RankedFeedItem{
+					FeedItem: entity.FeedItem{Post: entity.Post{ID: id, AuthorID: p.author}},
+					Score:    p.score,
+					Features: map[string]float64{"diversity": 1},
+				}
+			}
+
+			got := r.diversify(items)
+			if len(got) != len(tt.wantOrder) {
+				t.Fatalf("got %d items, want %d", len(got), len(tt.wantOrder))
+			}
+			for i, item := range got {
+				if name := names[item.Post.ID]; name != tt.wantOrder[i] {
+					t.Errorf("item %d = %s, want %s", i, name, tt.wantOrder[i])
+				}
+				if math.Abs(item.Score-tt.wantScores[i]) > 1e-9 {
+					t.Errorf("item %d score = %v, want %v", i, item.Score, tt.wantScores[i])
+				}
+				if want := item.Score / scores[item.Post.ID]; math.Abs(item.Features["diversity"]-want) > 1e-9 {
+					t.Errorf("item %d diversity = %v, want %v", i, item.Features["diversity"], want)
+				}
+			}
+		})
+	}
+}
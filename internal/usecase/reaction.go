@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+var (
+	ErrUnknownReaction  = NewError(ErrValidation, "unknown_reaction", "emoji is not an allowed reaction")
+	ErrAlreadyReacted   = NewError(ErrConflict, "already_reacted", "you already reacted with this emoji")
+	ErrReactionNotFound = NewError(ErrNotFound, "reaction_not_found", "you have not reacted with this emoji")
+)
+
+type reactionService struct {
+	reactionRepo repo.Reaction
+	postRepo     repo.Post
+	commentRepo  repo.Comment
+	emoji        []string
+	gate         verifiedGate
+}
+
+// NewReactionUseCase accepts reactions with the given emoji. The like emoji
+// is always allowed, since likes are stored as reactions with it.
+func NewReactionUseCase(reactionRepo repo.Reaction, postRepo repo.Post, commentRepo repo.Comment, userRepo repo.User, emoji []string, unverified UnverifiedAccess) Reaction {
+	if !slices.Contains(emoji, entity.LikeEmoji) {
+		emoji = append([]string{entity.LikeEmoji}, emoji...)
+	}
+
+	return &reactionService{
+		reactionRepo: reactionRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		emoji:        emoji,
+		gate:         verifiedGate{userRepo: userRepo, access: unverified},
+	}
+}
+
+func (s *reactionService) AddReaction(ctx context.Context, userID uuid.UUID, target entity.ReactionTarget, emoji string) error {
+	if !slices.Contains(s.emoji, emoji) {
+		return ErrUnknownReaction.WithDetails(map[string]string{"emoji": "must be one of " + strings.Join(s.emoji, " ")})
+	}
+
+	err := s.gate.canWrite(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	targetType, targetID, err := s.resolve(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	reaction := &entity.Reaction{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emoji:      emoji,
+	}
+
+	err = s.reactionRepo.Create(ctx, reaction)
+	if errors.Is(err, ErrConflict) {
+		return ErrAlreadyReacted
+	}
+	// The target was deleted after resolve found it
+	if errors.Is(err, ErrNotFound) {
+		if targetType == entity.ReactionTargetComment {
+			return ErrCommentNotFound
+		}
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *reactionService) RemoveReaction(ctx context.Context, userID uuid.UUID, target entity.ReactionTarget, emoji string) error {
+	targetType, targetID, err := s.resolve(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	err = s.reactionRepo.Delete(ctx, userID, targetType, targetID, emoji)
+	if err != nil {
+		return lookupError(err, ErrReactionNotFound)
+	}
+
+	return nil
+}
+
+// GetReactionSummary counts the reactions per emoji. viewerID is uuid.Nil for
+// anonymous requests, which have no reactions of their own.
+func (s *reactionService) GetReactionSummary(ctx context.Context, viewerID uuid.UUID, target entity.ReactionTarget) (*entity.ReactionSummary, error) {
+	targetType, targetID, err := s.resolve(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := reactionSummaries(ctx, s.reactionRepo, viewerID, targetType, []uuid.UUID{targetID})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries[targetID], nil
+}
+
+// resolve checks that the target exists and, for comments, that the comment
+// belongs to the post.
+func (s *reactionService) resolve(ctx context.Context, target entity.ReactionTarget) (entity.ReactionTargetType, uuid.UUID, error) {
+	if target.CommentID == nil {
+		_, err := s.postRepo.GetByID(ctx, target.PostID)
+		if err != nil {
+			return "", uuid.Nil, lookupError(err, ErrPostNotFound)
+		}
+		return entity.ReactionTargetPost, target.PostID, nil
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, *target.CommentID)
+	if err != nil {
+		return "", uuid.Nil, lookupError(err, ErrCommentNotFound)
+	}
+	if comment.PostID != target.PostID || comment.IsDeleted() {
+		return "", uuid.Nil, ErrCommentNotFound
+	}
+
+	return entity.ReactionTargetComment, comment.ID, nil
+}
+
+// reactionSummaries loads the reactions of any number of targets with one
+// query for the counts and one for the viewer's own reactions.
+func reactionSummaries(ctx context.Context, reactionRepo repo.Reaction, viewerID uuid.UUID, targetType entity.ReactionTargetType, targetIDs []uuid.UUID) (map[uuid.UUID]*entity.ReactionSummary, error) {
+	counts, err := reactionRepo.GetCounts(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	mine := map[uuid.UUID][]string{}
+	if viewerID != uuid.Nil {
+		mine, err = reactionRepo.GetUserReactions(ctx, viewerID, targetType, targetIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get own reactions: %w", err)
+		}
+	}
+
+	summaries := make(map[uuid.UUID]*entity.ReactionSummary, len(targetIDs))
+	for _, id := range targetIDs {
+		summary := &entity.ReactionSummary{Counts: counts[id], Mine: mine[id]}
+		if summary.Counts == nil {
+			summary.Counts = map[string]int{}
+		}
+		if summary.Mine == nil {
+			summary.Mine = []string{}
+		}
+		summaries[id] = summary
+	}
+
+	return summaries, nil
+}
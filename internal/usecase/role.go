@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+var ErrUnknownRole = NewError(ErrValidation, "unknown_role", "unknown role")
+
+// isModerator reports whether the user may remove other people's content.
+// Roles are read from the database rather than the access token, so a
+// revoked role stops working immediately.
+func isModerator(ctx context.Context, userRepo repo.User, userID uuid.UUID) (bool, error) {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, lookupError(err, ErrUserNotFound)
+	}
+
+	return entity.HasAnyRole(user.Roles, entity.RoleAdmin, entity.RoleModerator), nil
+}
+
+// SetRoles replaces the roles of a user. It takes effect in new access tokens.
+func (s *userService) SetRoles(ctx context.Context, username string, roles []string) (*entity.User, error) {
+	for _, role := range roles {
+		if !slices.Contains(entity.Roles, role) {
+			return nil, ErrUnknownRole.WithDetails(map[string]string{"roles": "unknown role " + role})
+		}
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	roles = slices.Compact(slices.Sorted(slices.Values(roles)))
+	if roles == nil {
+		roles = []string{}
+	}
+
+	err = s.userRepo.SetRoles(ctx, user.ID, roles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set roles: %w", err)
+	}
+
+	user.Roles = roles
+
+	// Clear password before returning
+	user.Password = ""
+	return user, nil
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/pkg/jwt"
+)
+
+var (
+	ErrInvalidRefreshToken = NewError(ErrUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = NewError(ErrUnauthorized, "refresh_token_reused", "refresh token reused")
+	ErrSessionNotFound     = NewError(ErrNotFound, "session_not_found", "session not found")
+)
+
+type sessionService struct {
+	sessionRepo repo.Session
+	userRepo    repo.User
+	tokens      *jwt.Manager
+	refreshTTL  time.Duration
+}
+
+func NewSessionUseCase(sessionRepo repo.Session, userRepo repo.User, tokens *jwt.Manager, refreshTTL time.Duration) Session {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		tokens:      tokens,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+func (s *sessionService) Start(ctx context.Context, user *entity.User, client entity.Client) (*entity.AuthTokens, error) {
+	refreshToken, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	session := &entity.Session{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+
+	err = s.sessionRepo.Create(ctx, session, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issue(user, session.ID, refreshToken)
+}
+
+// Authenticate checks that the session of an access token is still live, so
+// that logging out or revoking a session also ends its access tokens.
+func (s *sessionService) Authenticate(ctx context.Context, sessionID, userID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return lookupError(err, ErrAccessTokenRevoked)
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrAccessTokenRevoked
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return ErrAccessTokenExpired
+	}
+
+	return nil
+}
+
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string) (*entity.AuthTokens, error) {
+	oldHash := hashToken(refreshToken)
+
+	token, err := s.sessionRepo.GetRefreshToken(ctx, oldHash)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// A rotated token must never come back; if it does, either the client or
+	// an attacker holds a stolen copy, so the whole session is killed.
+	if token.RotatedAt != nil {
+		return nil, s.revokeReused(ctx, token.SessionID)
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, token.SessionID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	rotated, err := s.sessionRepo.RotateRefreshToken(ctx, oldHash, newHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !rotated {
+		return nil, s.revokeReused(ctx, session.ID)
+	}
+
+	return s.issue(user, session.ID, newToken)
+}
+
+func (s *sessionService) Logout(ctx context.Context, sessionID, userID uuid.UUID) error {
+	return s.RevokeSession(ctx, sessionID, userID)
+}
+
+func (s *sessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID, userID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return lookupError(err, ErrSessionNotFound)
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	err = s.sessionRepo.Revoke(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions signs the user out everywhere but the current session.
+func (s *sessionService) RevokeOtherSessions(ctx context.Context, userID, currentID uuid.UUID) error {
+	err := s.sessionRepo.RevokeAllByUserID(ctx, userID, currentID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sessionService) revokeReused(ctx context.Context, sessionID uuid.UUID) error {
+	err := s.sessionRepo.Revoke(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session after refresh token reuse: %w", err)
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func (s *sessionService) issue(user *entity.User, sessionID uuid.UUID, refreshToken string) (*entity.AuthTokens, error) {
+	accessToken, expiresAt, err := s.tokens.Issue(jwt.Claims{
+		Subject:   user.ID.String(),
+		Username:  user.Username,
+		SessionID: sessionID.String(),
+		Roles:     user.Roles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &entity.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+		SessionID:    sessionID,
+	}, nil
+}
+
+// newOpaqueToken returns a random token for the client together with the
+// hash that is stored in its place.
+func newOpaqueToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
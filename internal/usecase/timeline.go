@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+// TimelinePolicy configures the materialized home timelines.
+type TimelinePolicy struct {
+	Workers   int
+	QueueSize int
+	// MaxFanOutFollowers is the follower count from which an author's posts
+	// are no longer copied to every follower but merged in at read time.
+	MaxFanOutFollowers int
+	BackfillPosts      int
+	RebuildPosts       int
+}
+
+type timelineJob struct {
+	post     *entity.Post
+	userID   uuid.UUID
+	authorID uuid.UUID
+	unfollow bool
+}
+
+// Timelines keeps every user's home timeline up to date. Writes are queued and
+// applied by background workers so posting and following stay fast; a
+// timeline that has never been built is rebuilt when it is first read.
+//
+// Each worker has its own queue. The follows and unfollows of one follower
+// and author always go to the same worker, so they are applied in order.
+type Timelines struct {
+	timelineRepo repo.Timeline
+	policy       TimelinePolicy
+	queues       []chan timelineJob
+	wg           sync.WaitGroup
+}
+
+// NewTimelines splits policy.QueueSize evenly over the workers' queues.
+func NewTimelines(timelineRepo repo.Timeline, policy TimelinePolicy) *Timelines {
+	workers := max(policy.Workers, 1)
+	queues := make([]chan timelineJob, workers)
+	for i := range queues {
+		queues[i] = make(chan timelineJob, max(policy.QueueSize/workers, 1))
+	}
+
+	return &Timelines{
+		timelineRepo: timelineRepo,
+		policy:       policy,
+		queues:       queues,
+	}
+}
+
+// Start runs the fan-out workers until Stop is called.
+func (t *Timelines) Start() {
+	for _, jobs := range t.queues {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			for job := range jobs {
+				if err := t.apply(job); err != nil {
+					log.Printf("failed to update timelines: %v", err)
+				}
+			}
+		}()
+	}
+}
+
+// Stop waits for the queued updates to be applied. Nothing may be queued
+// after Stop.
+func (t *Timelines) Stop() {
+	for _, jobs := range t.queues {
+		close(jobs)
+	}
+	t.wg.Wait()
+}
+
+// PostCreated queues the post for its author's followers.
+func (t *Timelines) PostCreated(ctx context.Context, post *entity.Post) {
+	t.enqueue(ctx, timelineJob{post: post})
+}
+
+// Followed queues a backfill of the author's recent posts.
+func (t *Timelines) Followed(ctx context.Context, authorID, followerID uuid.UUID) {
+	t.enqueue(ctx, timelineJob{userID: followerID, authorID: authorID})
+}
+
+// Unfollowed queues the removal of the author's posts.
+func (t *Timelines) Unfollowed(ctx context.Context, authorID, followerID uuid.UUID) {
+	t.enqueue(ctx, timelineJob{userID: followerID, authorID: authorID, unfollow: true})
+}
+
+// Read returns a page of the user's home feed.
+func (t *Timelines) Read(ctx context.Context, userID uuid.UUID, page entity.PageRequest) ([]entity.FeedItem, *entity.Cursor, error) {
+	built, err := t.timelineRepo.IsBuilt(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !built {
+		err = t.timelineRepo.Rebuild(ctx, userID, t.policy.RebuildPosts, t.policy.MaxFanOutFollowers)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return t.timelineRepo.Get(ctx, userID, page, t.policy.MaxFanOutFollowers)
+}
+
+// enqueue waits for room in the queue as long as the request lives. An update
+// that does not make it is lost until the timeline is rebuilt, so it is logged.
+func (t *Timelines) enqueue(ctx context.Context, job timelineJob) {
+	select {
+	case t.queue(job) <- job:
+	case <-ctx.Done():
+		log.Printf("failed to queue timeline update: %v", ctx.Err())
+	}
+}
+
+// queue picks the worker for the job: by follower and author for follows and
+// unfollows, by author for posts.
+func (t *Timelines) queue(job timelineJob) chan timelineJob {
+	h := fnv.New32a()
+	if job.post != nil {
+		h.Write(job.post.AuthorID[:])
+	} else {
+		h.Write(job.userID[:])
+		h.Write(job.authorID[:])
+	}
+	return t.queues[h.Sum32()%uint32(len(t.queues))]
+}
+
+func (t *Timelines) apply(job timelineJob) error {
+	ctx := context.Background()
+
+	switch {
+	case job.post != nil:
+		err := t.timelineRepo.FanOut(ctx, job.post, t.policy.MaxFanOutFollowers)
+		if err != nil {
+			return fmt.Errorf("post %s: %w", job.post.ID, err)
+		}
+	case job.unfollow:
+		err := t.timelineRepo.RemoveAuthor(ctx, job.userID, job.authorID)
+		if err != nil {
+			return fmt.Errorf("unfollow of %s by %s: %w", job.authorID, job.userID, err)
+		}
+	default:
+		err := t.timelineRepo.Backfill(ctx, job.userID, job.authorID, t.policy.BackfillPosts)
+		if err != nil {
+			return fmt.Errorf("follow of %s by %s: %w", job.authorID, job.userID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+var (
+	ErrInvalidAccessToken = NewError(ErrUnauthorized, "invalid_token", "invalid token")
+	ErrAccessTokenExpired = NewError(ErrUnauthorized, "token_expired", "token expired")
+	ErrAccessTokenRevoked = NewError(ErrUnauthorized, "token_revoked", "token revoked")
+	ErrTokenNotFound      = NewError(ErrNotFound, "token_not_found", "token not found")
+)
+
+var knownScopes = map[string]bool{
+	entity.ScopeRead:          true,
+	entity.ScopePostsWrite:    true,
+	entity.ScopeCommentsWrite: true,
+	entity.ScopeLikesWrite:    true,
+	entity.ScopeFollowsWrite:  true,
+	entity.ScopeProfileWrite:  true,
+}
+
+type tokenService struct {
+	tokenRepo repo.Token
+	userRepo  repo.User
+}
+
+func NewTokenUseCase(tokenRepo repo.Token, userRepo repo.User) Token {
+	return &tokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *tokenService) CreateToken(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*entity.Token, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", ValidationError("name", "token name is required")
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", ValidationError("scopes", "at least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		if !knownScopes[scope] {
+			return nil, "", ValidationError("scopes", "unknown scope: "+scope)
+		}
+	}
+
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", ValidationError("expires_at", "expiry must be in the future")
+	}
+
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	plaintext := entity.PersonalTokenPrefix + secret
+
+	token := &entity.Token{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	err = s.tokenRepo.Create(ctx, token, hashToken(plaintext))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return token, plaintext, nil
+}
+
+func (s *tokenService) ListTokens(ctx context.Context, userID uuid.UUID) ([]entity.Token, error) {
+	tokens, err := s.tokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *tokenService) RevokeToken(ctx context.Context, tokenID, userID uuid.UUID) error {
+	err := s.tokenRepo.Revoke(ctx, tokenID, userID)
+	if err != nil {
+		return lookupError(err, ErrTokenNotFound)
+	}
+
+	return nil
+}
+
+func (s *tokenService) Authenticate(ctx context.Context, plaintext string) (*entity.User, *entity.Token, error) {
+	token, err := s.tokenRepo.GetByHash(ctx, hashToken(plaintext))
+	if err != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	if token.RevokedAt != nil {
+		return nil, nil, ErrAccessTokenRevoked
+	}
+
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, nil, ErrAccessTokenExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	err = s.tokenRepo.TouchLastUsed(ctx, token.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update token: %w", err)
+	}
+
+	// Clear password before returning
+	user.Password = ""
+	return user, token, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+)
+
+// minTrendingAge is the shortest time engagement is spread over, so that a
+// post does not trend off the first reaction in its first minutes.
+const minTrendingAge = time.Hour
+
+// TrendingPolicy configures how trending posts are computed. Every Interval
+// the Size posts with the fastest engagement over the last Window are stored.
+type TrendingPolicy struct {
+	Interval time.Duration
+	Window   time.Duration
+	Size     int
+}
+
+// TrendingRefresher precomputes the trending posts in the background, so
+// reading them is a single indexed query.
+type TrendingRefresher struct {
+	trendingRepo repo.Trending
+	policy       TrendingPolicy
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+func NewTrendingRefresher(trendingRepo repo.Trending, policy TrendingPolicy) *TrendingRefresher {
+	return &TrendingRefresher{
+		trendingRepo: trendingRepo,
+		policy:       policy,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start refreshes the trending posts now and then every Interval until Stop
+// is called.
+func (t *TrendingRefresher) Start() {
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(t.policy.Interval)
+		defer ticker.Stop()
+
+		for {
+			t.refresh()
+
+			select {
+			case <-ticker.C:
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop waits for a running refresh to finish.
+func (t *TrendingRefresher) Stop() {
+	close(t.stop)
+	<-t.done
+}
+
+func (t *TrendingRefresher) refresh() {
+	err := t.Refresh(context.Background(), time.Now())
+	if err != nil {
+		log.Printf("failed to refresh trending posts: %v", err)
+	}
+}
+
+// Refresh replaces the trending posts with those that gained engagement the
+// fastest in the Window before now.
+func (t *TrendingRefresher) Refresh(ctx context.Context, now time.Time) error {
+	since := now.Add(-t.policy.Window)
+	engagement, err := t.trendingRepo.GetEngagement(ctx, since)
+	if err != nil {
+		return fmt.Errorf("failed to get engagement: %w", err)
+	}
+
+	scores := make([]entity.TrendingScore, len(engagement))
+	for i, e := range engagement {
+		scores[i] = entity.TrendingScore{PostID: e.PostID, Score: velocity(e, since, now)}
+	}
+	slices.SortFunc(scores, func(a, b entity.TrendingScore) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.PostID[:], b.PostID[:])
+	})
+
+	err = t.trendingRepo.Replace(ctx, scores[:min(len(scores), t.policy.Size)])
+	if err != nil {
+		return fmt.Errorf("failed to store trending posts: %w", err)
+	}
+
+	return nil
+}
+
+// velocity is the post's engagement per hour over the time it could gather
+// it: since it was posted, or since the window opened for older posts, but
+// at least minTrendingAge.
+func velocity(e entity.PostEngagement, since, now time.Time) float64 {
+	start := e.CreatedAt
+	if start.Before(since) {
+		start = since
+	}
+
+	return e.Engagement / max(now.Sub(start), minTrendingAge).Hours()
+}
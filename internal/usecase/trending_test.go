@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+)
+
+// memoryTrending serves fixed engagement and keeps the scores it is given.
+type memoryTrending struct {
+	engagement []entity.PostEngagement
+	scores     []entity.TrendingScore
+}
+
+func (m *memoryTrending) GetEngagement(_ context.Context, _ time.Time) ([]entity.PostEngagement, error) {
+	return m.engagement, nil
+}
+
+func (m *memoryTrending) Replace(_ context.Context, scores []entity.TrendingScore) error {
+	m.scores = scores
+	return nil
+}
+
+func (m *memoryTrending) Get(_ context.Context, _ int) ([]entity.Post, error) {
+	return nil, nil
+}
+
+func TestVelocity(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		createdAt  time.Time
+		engagement float64
+		want       float64
+	}{
+		{"posted before the window", now.Add(-72 * time.Hour), 48, 2},
+		{"posted as the window opened", since, 48, 2},
+		{"posted within the window", now.Add(-4 * time.Hour), 48, 12},
+		{"posted minutes ago", now.Add(-10 * time.Minute), 50, 50},
+		{"no engagement", now.Add(-4 * time.Hour), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := velocity(entity.PostEngagement{CreatedAt: tt.createdAt, Engagement: tt.engagement}, since, now)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("velocity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrendingRefresh(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rising, steady, recent, quiet := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		size       int
+		engagement []entity.PostEngagement
+		want       []uuid.UUID
+	}{
+		{
+			name: "fast riser beats more total engagement",
+			size: 10,
+			engagement: []entity.PostEngagement{
+				{PostID: steady, CreatedAt: now.Add(-30 * time.Hour), Engagement: 51},
+				{PostID: rising, CreatedAt: now.Add(-10 * time.Minute), Engagement: 50},
+			},
+			want: []uuid.UUID{rising, steady},
+		},
+		{
+			name: "younger post with the same engagement ranks higher",
+			size: 10,
+			engagement: []entity.PostEngagement{
+				{PostID: steady, CreatedAt: now.Add(-12 * time.Hour), Engagement: 20},
+				{PostID: recent, CreatedAt: now.Add(-2 * time.Hour), Engagement: 20},
+			},
+			want: []uuid.UUID{recent, steady},
+		},
+		{
+			name: "keeps the top size posts",
+			size: 2,
+			engagement: []entity.PostEngagement{
+				{PostID: quiet, CreatedAt: now.Add(-20 * time.Hour), Engagement: 1},
+				{PostID: steady, CreatedAt: now.Add(-30 * time.Hour), Engagement: 51},
+				{PostID: rising, CreatedAt: now.Add(-10 * time.Minute), Engagement: 50},
+			},
+			want: []uuid.UUID{rising, steady},
+		},
+		{
+			name: "nothing to trend",
+			size: 10,
+			want: []uuid.UUID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trendingRepo := &memoryTrending{engagement: tt.engagement}
+			refresher := NewTrendingRefresher(trendingRepo, TrendingPolicy{Window: 24 * time.Hour, Size: tt.size})
+
+			err := refresher.Refresh(context.Background(), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(trendingRepo.scores) != len(tt.want) {
+				t.Fatalf("stored %d posts, want %d", len(trendingRepo.scores), len(tt.want))
+			}
+			for i, score := range trendingRepo.scores {
+				if score.PostID != tt.want[i] {
+					t.Errorf("post %d = %s, want %s", i, score.PostID, tt.want[i])
+				}
+				if i > 0 && score.Score > trendingRepo.scores[i-1].Score {
+					t.Errorf("post %d scores %v, more than the post before it", i, score.Score)
+				}
+			}
+		})
+	}
+}
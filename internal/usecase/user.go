@@ -3,33 +3,70 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"social/api/internal/entity"
 	"social/api/internal/repo"
+	"social/api/pkg/jwt"
+	"social/api/pkg/secretbox"
 )
 
 type userService struct {
-	userRepo repo.User
+	userRepo     repo.User
+	mfaRepo      repo.MFA
+	sessions     Session
+	verification Verification
+	guard        *LoginGuard
+	tokens       *jwt.Manager
+	secrets      *secretbox.Box
+	totpIssuer   string
+	// usernameInterval is the minimum time between two username changes
+	usernameInterval time.Duration
 }
 
-func NewUserUseCase(userRepo repo.User) User {
+func NewUserUseCase(userRepo repo.User, mfaRepo repo.MFA, sessions Session, verification Verification, guard *LoginGuard, tokens *jwt.Manager, secrets *secretbox.Box, totpIssuer string, usernameInterval time.Duration) User {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		mfaRepo:          mfaRepo,
+		sessions:         sessions,
+		verification:     verification,
+		guard:            guard,
+		tokens:           tokens,
+		secrets:          secrets,
+		totpIssuer:       totpIssuer,
+		usernameInterval: usernameInterval,
 	}
 }
 
 func (s *userService) Register(ctx context.Context, name, username, email, password string) (*entity.User, error) {
+	name = strings.TrimSpace(name)
+	username = strings.TrimSpace(username)
+	email = strings.TrimSpace(email)
+
+	for _, err := range []error{
+		validateText("name", name, true, maxNameLength),
+		validateUsername(username),
+		validateEmail(email),
+		validatePassword("password", password),
+	} {
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user already exists
 	_, err := s.userRepo.GetByEmail(ctx, email)
 	if err == nil {
-		return nil, fmt.Errorf("user with this email already exists")
+		return nil, ErrEmailTaken
 	}
 
 	_, err = s.userRepo.GetByUsername(ctx, username)
 	if err == nil {
-		return nil, fmt.Errorf("user with this username already exists")
+		return nil, ErrUsernameTaken
 	}
 
 	// Hash password
@@ -50,31 +87,78 @@ func (s *userService) Register(ctx context.Context, name, username, email, passw
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// The account exists either way; a lost email can be resent
+	err = s.verification.SendVerification(ctx, user)
+	if err != nil {
+		log.Printf("failed to send verification email: %v", err)
+	}
+
 	// Clear password before returning
 	user.Password = ""
 	return user, nil
 }
 
-func (s *userService) Login(ctx context.Context, email, password string) (string, error) {
+func (s *userService) Login(ctx context.Context, email, password string, client entity.Client) (*entity.LoginResult, error) {
+	err := s.guard.Check(ctx, email, client.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", fmt.Errorf("invalid credentials")
+		return nil, s.loginFailed(ctx, nil, email, client)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", fmt.Errorf("invalid credentials")
+		return nil, s.loginFailed(ctx, &user.ID, email, client)
 	}
 
-	// In a real implementation, you would generate a JWT token here
-	// For now, we'll just return a placeholder
-	return "jwt-token-placeholder", nil
+	// Clear password before returning
+	user.Password = ""
+
+	result, err := startLogin(ctx, s.mfaRepo, s.sessions, s.tokens, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts with 2FA are only done once LoginMFA succeeds
+	if result.Tokens != nil {
+		err = s.guard.Succeed(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// loginFailed counts the failure and reports it as invalid credentials, or as
+// a LockoutError once the attempt pushed the email or IP over its limit.
+func (s *userService) loginFailed(ctx context.Context, userID *uuid.UUID, email string, client entity.Client) error {
+	err := s.guard.Fail(ctx, userID, email, client.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	return ErrInvalidCredentials
 }
 
 func (s *userService) GetProfile(ctx context.Context, username string) (*entity.User, error) {
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	// Clear password before returning
+	user.Password = ""
+	return user, nil
+}
+
+func (s *userService) GetProfileByID(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, ErrUserNotFound)
 	}
 
 	// Clear password before returning
@@ -82,14 +166,28 @@ func (s *userService) GetProfile(ctx context.Context, username string) (*entity.
 	return user, nil
 }
 
-func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, name, bio, imageURL *string) (*entity.User, error) {
+func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, name, bio, imageURL *string, privateLikes *bool) (*entity.User, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, lookupError(err, ErrUserNotFound)
 	}
 
 	if name != nil {
-		user.Name = *name
+		err = validateText("name", strings.TrimSpace(*name), true, maxNameLength)
+		if err != nil {
+			return nil, err
+		}
+		user.Name = strings.TrimSpace(*name)
+	}
+	if bio != nil {
+		err = validateText("bio", *bio, false, maxBioLength)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = validateImageURL("image_url", imageURL)
+	if err != nil {
+		return nil, err
 	}
 	if bio != nil {
 		user.Bio = bio
@@ -97,6 +195,9 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, name,
 	if imageURL != nil {
 		user.ImageURL = imageURL
 	}
+	if privateLikes != nil {
+		user.PrivateLikes = *privateLikes
+	}
 
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
@@ -120,4 +221,4 @@ func (s *userService) SearchUsers(ctx context.Context, query string) ([]entity.U
 	}
 
 	return users, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Domain limits. They are checked here rather than in the transport so every
+// caller of the use cases gets the same rules.
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+	maxNameLength     = 100
+	maxEmailLength    = 100
+	maxBioLength      = 500
+	maxPostLength     = 2000
+	maxCommentLength  = 1000
+	maxCommentDepth   = 5
+	maxURLLength      = 2048
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+func validateUsername(username string) error {
+	if username == "" {
+		return ValidationError("username", "username is required")
+	}
+	if len(username) < minUsernameLength || len(username) > maxUsernameLength {
+		return ValidationError("username", fmt.Sprintf("username must be between %d and %d characters", minUsernameLength, maxUsernameLength))
+	}
+	if !usernamePattern.MatchString(username) {
+		return ValidationError("username", "username may only contain letters, digits and underscores")
+	}
+
+	return nil
+}
+
+func validateEmail(email string) error {
+	if email == "" {
+		return ValidationError("email", "email is required")
+	}
+	if len(email) > maxEmailLength {
+		return ValidationError("email", fmt.Sprintf("email must be at most %d characters", maxEmailLength))
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ValidationError("email", "email must be a valid email address")
+	}
+
+	return nil
+}
+
+func validatePassword(field, password string) error {
+	if len(password) < minPasswordLength {
+		return ErrPasswordTooShort.WithDetails(map[string]string{field: ErrPasswordTooShort.Message})
+	}
+
+	return nil
+}
+
+// validateText checks free text such as names, bios, posts and comments.
+func validateText(field, text string, required bool, maxLength int) error {
+	if required && strings.TrimSpace(text) == "" {
+		return ValidationError(field, field+" is required")
+	}
+	if utf8.RuneCountInString(text) > maxLength {
+		return ValidationError(field, fmt.Sprintf("%s must be at most %d characters", field, maxLength))
+	}
+
+	return nil
+}
+
+func validateImageURL(field string, imageURL *string) error {
+	if imageURL == nil || *imageURL == "" {
+		return nil
+	}
+	if len(*imageURL) > maxURLLength {
+		return ValidationError(field, fmt.Sprintf("%s must be at most %d characters", field, maxURLLength))
+	}
+
+	u, err := url.Parse(*imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ValidationError(field, field+" must be an http or https URL")
+	}
+
+	return nil
+}
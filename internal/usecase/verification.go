@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"social/api/internal/entity"
+	"social/api/internal/repo"
+	"social/api/pkg/mailer"
+)
+
+// UnverifiedAccess limits what accounts without a verified email may do.
+type UnverifiedAccess string
+
+const (
+	UnverifiedFull UnverifiedAccess = "full"
+	// UnverifiedNoPosts blocks creating and editing posts.
+	UnverifiedNoPosts UnverifiedAccess = "no_posts"
+	// UnverifiedReadOnly additionally blocks comments, likes and follows.
+	UnverifiedReadOnly UnverifiedAccess = "read_only"
+)
+
+var (
+	ErrEmailNotVerified         = NewError(ErrForbidden, "email_not_verified", "verify your email address first")
+	ErrEmailAlreadyVerified     = NewError(ErrConflict, "email_already_verified", "email address is already verified")
+	ErrInvalidVerificationToken = NewError(ErrValidation, "invalid_verification_token", "invalid or expired verification token")
+)
+
+func ParseUnverifiedAccess(s string) (UnverifiedAccess, error) {
+	switch access := UnverifiedAccess(s); access {
+	case UnverifiedFull, UnverifiedNoPosts, UnverifiedReadOnly:
+		return access, nil
+	}
+
+	return "", fmt.Errorf("unknown unverified access %q", s)
+}
+
+// verifiedGate is shared by the use cases that restrict unverified accounts.
+type verifiedGate struct {
+	userRepo repo.User
+	access   UnverifiedAccess
+}
+
+// canPost checks whether userID may create or edit posts.
+func (g verifiedGate) canPost(ctx context.Context, userID uuid.UUID) error {
+	if g.access == UnverifiedFull {
+		return nil
+	}
+
+	return g.requireVerified(ctx, userID)
+}
+
+// canWrite checks whether userID may comment, like or follow.
+func (g verifiedGate) canWrite(ctx context.Context, userID uuid.UUID) error {
+	if g.access != UnverifiedReadOnly {
+		return nil
+	}
+
+	return g.requireVerified(ctx, userID)
+}
+
+func (g verifiedGate) requireVerified(ctx context.Context, userID uuid.UUID) error {
+	user, err := g.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, ErrUserNotFound)
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+type verificationService struct {
+	userRepo  repo.User
+	tokenRepo repo.OneTimeToken
+	mailer    mailer.Mailer
+	verifyURL string
+	ttl       time.Duration
+}
+
+func NewVerificationUseCase(userRepo repo.User, tokenRepo repo.OneTimeToken, m mailer.Mailer, verifyURL string, ttl time.Duration) Verification {
+	return &verificationService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    m,
+		verifyURL: verifyURL,
+		ttl:       ttl,
+	}
+}
+
+// SendVerification emails a link that verifies the user's current address.
+// Links sent earlier stop working.
+func (s *verificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	err := s.tokenRepo.DeleteByUserID(ctx, user.ID, entity.PurposeEmailVerification)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	err = s.tokenRepo.Create(ctx, &entity.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   entity.PurposeEmailVerification,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	sendInBackground(ctx, s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n",
+			user.Name, s.ttl, linkWithToken(s.verifyURL, token)),
+	})
+
+	return nil
+}
+
+func (s *verificationService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, ErrUserNotFound)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+func (s *verificationService) VerifyEmail(ctx context.Context, token string) error {
+	verification, err := s.tokenRepo.Consume(ctx, hashToken(token), entity.PurposeEmailVerification)
+	if err != nil {
+		return fmt.Errorf("failed to check verification token: %w", err)
+	}
+	if verification == nil {
+		return ErrInvalidVerificationToken
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(ctx, verification.UserID, verification.Email)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	if !verified {
+		// The address changed after the link was sent
+		return ErrInvalidVerificationToken
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    -- scope is either 'email' or 'ip'
+    scope VARCHAR(10) NOT NULL,
+    key TEXT NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE TABLE IF NOT EXISTS auth_audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event VARCHAR(50) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON auth_audit_events (user_id, created_at);
+CREATE INDEX ON auth_audit_events (email, created_at);
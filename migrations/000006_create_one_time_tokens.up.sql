@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- purpose keeps e.g. a password reset token from being used elsewhere
+    purpose VARCHAR(30) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX ON one_time_tokens (user_id, purpose);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- username is the name the user had before this change
+    username VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON username_history (username, changed_at);
+CREATE INDEX ON username_history (user_id, changed_at);
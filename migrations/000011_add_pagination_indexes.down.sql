@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS followers_follower_created_at_idx;
+DROP INDEX IF EXISTS followers_user_created_at_idx;
+DROP INDEX IF EXISTS comments_post_created_at_idx;
+DROP INDEX IF EXISTS posts_created_at_idx;
+DROP INDEX IF EXISTS posts_author_created_at_idx;
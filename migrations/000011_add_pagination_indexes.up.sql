@@ -0,0 +1,6 @@
+-- Keyset pagination walks these lists by (created_at, id)
+CREATE INDEX IF NOT EXISTS posts_author_created_at_idx ON posts (author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_created_at_idx ON posts (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS comments_post_created_at_idx ON comments (post_id, created_at, id);
+CREATE INDEX IF NOT EXISTS followers_user_created_at_idx ON followers (user_id, created_at DESC, follower_id DESC);
+CREATE INDEX IF NOT EXISTS followers_follower_created_at_idx ON followers (follower_id, created_at DESC, user_id DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timeline_builds;
+DROP TABLE IF EXISTS timelines;
+ALTER TABLE users DROP COLUMN IF EXISTS follower_count;
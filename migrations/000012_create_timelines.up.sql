@@ -0,0 +1,21 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS follower_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users u SET follower_count = (SELECT COUNT(*) FROM followers f WHERE f.user_id = u.id);
+
+-- Materialized home timelines, filled by fan-out on write
+CREATE TABLE IF NOT EXISTS timelines (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX ON timelines (user_id, created_at DESC, post_id DESC);
+CREATE INDEX ON timelines (user_id, author_id);
+
+-- Users whose timeline has been built; the others are rebuilt on first read
+CREATE TABLE IF NOT EXISTS timeline_builds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    built_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
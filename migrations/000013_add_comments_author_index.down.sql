@@ -0,0 +1 @@
+DROP INDEX IF EXISTS comments_author_created_at_idx;
@@ -0,0 +1,2 @@
+-- The For You ranker counts a viewer's recent comments per author
+CREATE INDEX IF NOT EXISTS comments_author_created_at_idx ON comments (author_id, created_at);
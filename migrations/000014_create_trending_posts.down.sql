@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS comments_created_at_idx;
+DROP INDEX IF EXISTS likes_created_at_idx;
+DROP TABLE IF EXISTS trending_posts;
@@ -0,0 +1,12 @@
+-- Refreshed periodically from recent likes and comments
+CREATE TABLE IF NOT EXISTS trending_posts (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS trending_posts_score_idx ON trending_posts (score DESC, post_id);
+
+-- The refresh scans the engagement of the trending window
+CREATE INDEX IF NOT EXISTS likes_created_at_idx ON likes (created_at);
+CREATE INDEX IF NOT EXISTS comments_created_at_idx ON comments (created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS comment_count;
+ALTER TABLE posts DROP COLUMN IF EXISTS like_count;
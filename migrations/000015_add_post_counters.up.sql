@@ -0,0 +1,7 @@
+-- Kept in step by the like and comment repositories
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS like_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE posts p SET
+    like_count = (SELECT COUNT(*) FROM likes l WHERE l.post_id = p.id),
+    comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS likes_user_created_at_idx;
+DROP INDEX IF EXISTS likes_post_created_at_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS private_likes;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS private_likes BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Likers of a post and liked posts of a user, newest like first
+CREATE INDEX IF NOT EXISTS likes_post_created_at_idx ON likes (post_id, created_at DESC, user_id DESC);
+CREATE INDEX IF NOT EXISTS likes_user_created_at_idx ON likes (user_id, created_at DESC, post_id DESC);
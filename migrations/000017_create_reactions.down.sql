@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS likes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS likes_post_id_idx ON likes (post_id);
+CREATE INDEX IF NOT EXISTS likes_created_at_idx ON likes (created_at);
+CREATE INDEX IF NOT EXISTS likes_post_created_at_idx ON likes (post_id, created_at DESC, user_id DESC);
+CREATE INDEX IF NOT EXISTS likes_user_created_at_idx ON likes (user_id, created_at DESC, post_id DESC);
+
+-- Only 👍 reactions to posts that still exist can become likes again
+INSERT INTO likes (user_id, post_id, created_at)
+SELECT r.user_id, r.target_id, r.created_at
+FROM reactions r
+JOIN posts p ON p.id = r.target_id
+WHERE r.target_type = 'post' AND r.emoji = '👍';
+
+DROP TABLE IF EXISTS reactions;
@@ -0,0 +1,24 @@
+-- Reactions to posts and comments replace likes; a like is a 👍 reaction.
+-- target_id refers to posts or comments depending on target_type, so the
+-- repositories delete the reactions of deleted posts and comments.
+CREATE TABLE IF NOT EXISTS reactions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+    target_id UUID NOT NULL,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, target_type, target_id, emoji)
+);
+
+-- Counts per target and the users behind one emoji, newest first
+CREATE INDEX IF NOT EXISTS reactions_target_created_at_idx ON reactions (target_type, target_id, emoji, created_at DESC, user_id DESC);
+-- Posts a user reacted to with one emoji, newest first
+CREATE INDEX IF NOT EXISTS reactions_user_created_at_idx ON reactions (user_id, target_type, emoji, created_at DESC, target_id DESC);
+-- Engagement of the trending window
+CREATE INDEX IF NOT EXISTS reactions_created_at_idx ON reactions (created_at);
+
+INSERT INTO reactions (user_id, target_type, target_id, emoji, created_at)
+SELECT user_id, 'post', post_id, '👍', created_at FROM likes
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS likes;
@@ -0,0 +1,14 @@
+-- Reposts and quote posts point at their original without a foreign key, so
+-- they outlive it as tombstones when it is deleted
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_of_id UUID;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS quote_of_id UUID;
+ALTER TABLE posts ADD CONSTRAINT posts_repost_or_quote CHECK (repost_of_id IS NULL OR quote_of_id IS NULL);
+
+-- Kept in step by the post repository
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS quote_count INTEGER NOT NULL DEFAULT 0;
+
+-- A user reposts a post at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_repost ON posts(author_id, repost_of_id) WHERE repost_of_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_repost_of_id ON posts(repost_of_id) WHERE repost_of_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_quote_of_id ON posts(quote_of_id) WHERE quote_of_id IS NOT NULL;
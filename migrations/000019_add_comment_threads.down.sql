@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS comments_parent_idx;
+DROP INDEX IF EXISTS comments_post_path_idx;
+
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS path;
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS reply_count;
+ALTER TABLE comments DROP COLUMN IF EXISTS depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_comment_id;
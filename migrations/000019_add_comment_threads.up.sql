@@ -0,0 +1,21 @@
+-- Replies keep their place in the thread if the parent's author is deleted
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_comment_id UUID REFERENCES comments(id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+
+-- Visible direct replies, kept in step by the comment repository
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+
+-- Deleted comments stay as placeholders while they have replies
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- The parent's path followed by the comment's own segment, so that sorting
+-- by path walks a thread depth first. Segments are the UTC creation time to
+-- the microsecond followed by the ID, compared bytewise.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS path TEXT COLLATE "C";
+
+UPDATE comments SET path = to_char(created_at AT TIME ZONE 'UTC', 'YYYYMMDDHH24MISSUS') || id::text WHERE path IS NULL;
+
+ALTER TABLE comments ALTER COLUMN path SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS comments_post_path_idx ON comments (post_id, path);
+CREATE INDEX IF NOT EXISTS comments_parent_idx ON comments (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
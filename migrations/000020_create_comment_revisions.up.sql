@@ -0,0 +1,16 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE comments SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE comments ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE comments ALTER COLUMN updated_at SET DEFAULT NOW();
+
+-- Prior versions of edited comments. created_at is when the version was
+-- written and replaced_at when an edit replaced it.
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS comment_revisions_comment_idx ON comment_revisions (comment_id, created_at);
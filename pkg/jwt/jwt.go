@@ -0,0 +1,167 @@
+// Package jwt implements HS256 signed JSON Web Tokens.
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	_defaultTTL    = time.Hour
+	_defaultIssuer = "social-api"
+)
+
+var (
+	// ErrMalformedToken is returned when a token cannot be decoded.
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrInvalidSignature is returned when a token was not signed with the configured secret.
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrTokenExpired is returned when a token is past its expiry.
+	ErrTokenExpired = errors.New("token expired")
+	// ErrInvalidIssuer is returned when a token was issued by someone else.
+	ErrInvalidIssuer = errors.New("invalid token issuer")
+)
+
+// Claims -.
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Username  string   `json:"username"`
+	SessionID string   `json:"sid,omitempty"`
+	Purpose   string   `json:"purpose,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// Manager -.
+type Manager struct {
+	secret []byte
+	ttl    time.Duration
+	issuer string
+	now    func() time.Time
+}
+
+// New -.
+func New(secret string, opts ...Option) *Manager {
+	m := &Manager{
+		secret: []byte(secret),
+		ttl:    _defaultTTL,
+		issuer: _defaultIssuer,
+		now:    time.Now,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Issue signs an access token carrying the given claims and returns it with
+// its expiry. Issuer and timestamps are always set by the manager.
+func (m *Manager) Issue(claims Claims) (string, time.Time, error) {
+	return m.IssueWithTTL(claims, m.ttl)
+}
+
+// IssueWithTTL is like Issue but overrides the configured lifetime.
+func (m *Manager) IssueWithTTL(claims Claims, ttl time.Duration) (string, time.Time, error) {
+	now := m.now()
+	expiresAt := now.Add(ttl)
+
+	claims.Issuer = m.issuer
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = expiresAt.Unix()
+
+	token, err := m.sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// Parse verifies the token signature, expiry and issuer and returns its claims.
+func (m *Manager) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if !hmac.Equal(signature, m.signature(parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims Claims
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if m.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	if claims.Issuer != m.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	return &claims, nil
+}
+
+func (m *Manager) sign(claims Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("jwt - sign - json.Marshal header: %w", err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt - sign - json.Marshal claims: %w", err)
+	}
+
+	unsigned := encode(h) + "." + encode(payload)
+
+	return unsigned + "." + encode(m.signature(unsigned)), nil
+}
+
+func (m *Manager) signature(unsigned string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(unsigned))
+
+	return mac.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSON(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,100 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"social/api/pkg/jwt"
+)
+
+const secret = "test-secret"
+
+func issue(t *testing.T, m *jwt.Manager, ttl time.Duration) string {
+	t.Helper()
+
+	token, _, err := m.IssueWithTTL(jwt.Claims{
+		Subject:   "user-1",
+		Username:  "alice",
+		SessionID: "session-1",
+		Roles:     []string{"admin"},
+	}, ttl)
+	if err != nil {
+		t.Fatalf("IssueWithTTL: %v", err)
+	}
+
+	return token
+}
+
+func TestIssueAndParse(t *testing.T) {
+	m := jwt.New(secret, jwt.TTL(time.Minute), jwt.Issuer("test"))
+
+	token, expiresAt, err := m.Issue(jwt.Claims{Subject: "user-1", Issuer: "someone-else"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expiresAt); d <= 0 || d > time.Minute {
+		t.Errorf("expires in %s, want within a minute", d)
+	}
+
+	claims, err := m.Parse(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user-1" {
+		t.Errorf("Subject = %q, want %q", claims.Subject, "user-1")
+	}
+	if claims.Issuer != "test" {
+		t.Errorf("Issuer = %q, want the manager's issuer %q", claims.Issuer, "test")
+	}
+	if claims.ExpiresAt != expiresAt.Unix() {
+		t.Errorf("ExpiresAt = %d, want %d", claims.ExpiresAt, expiresAt.Unix())
+	}
+}
+
+func TestParse(t *testing.T) {
+	m := jwt.New(secret)
+	valid := issue(t, m, time.Minute)
+	parts := strings.Split(valid, ".")
+
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"social-api","sub":"user-2","exp":9999999999}`))
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name    string
+		manager *jwt.Manager
+		token   string
+		wantErr error
+	}{
+		{"valid", m, valid, nil},
+		{"expired", m, issue(t, m, -time.Minute), jwt.ErrTokenExpired},
+		{"other secret", jwt.New("other-secret"), valid, jwt.ErrInvalidSignature},
+		{"other issuer", jwt.New(secret, jwt.Issuer("other")), valid, jwt.ErrInvalidIssuer},
+		{"tampered claims", m, parts[0] + "." + tampered + "." + parts[2], jwt.ErrInvalidSignature},
+		{"alg none", m, unsigned + "." + parts[1] + ".", jwt.ErrMalformedToken},
+		{"bad signature encoding", m, parts[0] + "." + parts[1] + ".!!!", jwt.ErrMalformedToken},
+		{"two parts", m, parts[0] + "." + parts[1], jwt.ErrMalformedToken},
+		{"empty", m, "", jwt.ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.manager.Parse(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if claims.Subject != "user-1" || claims.Username != "alice" || claims.SessionID != "session-1" {
+				t.Errorf("claims = %+v, want those that were issued", claims)
+			}
+			if len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+				t.Errorf("Roles = %v, want [admin]", claims.Roles)
+			}
+		})
+	}
+}
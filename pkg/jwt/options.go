@@ -0,0 +1,20 @@
+package jwt
+
+import "time"
+
+// Option -.
+type Option func(*Manager)
+
+// TTL -.
+func TTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// Issuer -.
+func Issuer(issuer string) Option {
+	return func(m *Manager) {
+		m.issuer = issuer
+	}
+}
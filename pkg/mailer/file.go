@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File writes every message to its own file in a directory, so local
+// development can read links out of emails without a mail server.
+type File struct {
+	dir string
+}
+
+var _ Mailer = (*File)(nil)
+
+// NewFile -.
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("mailer - NewFile - os.MkdirAll: %w", err)
+	}
+
+	return &File{dir: dir}, nil
+}
+
+// Send -.
+func (f *File) Send(_ context.Context, msg Message) error {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("mailer - File - Send - rand.Read: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000"), hex.EncodeToString(suffix))
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+
+	err := os.WriteFile(filepath.Join(f.dir, name), []byte(content), 0o600)
+	if err != nil {
+		return fmt.Errorf("mailer - File - Send - os.WriteFile: %w", err)
+	}
+
+	return nil
+}
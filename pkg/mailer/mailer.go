@@ -0,0 +1,16 @@
+// Package mailer sends transactional email.
+package mailer
+
+import "context"
+
+// Message -.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer -.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
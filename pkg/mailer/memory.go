@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory keeps sent messages in memory for tests to inspect.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+var _ Mailer = (*Memory)(nil)
+
+// NewMemory -.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Send -.
+func (m *Memory) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far.
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
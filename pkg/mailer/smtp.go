@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// SMTP delivers messages through an SMTP relay.
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+var _ Mailer = (*SMTP)(nil)
+
+// NewSMTP -.
+func NewSMTP(host, port, username, password, from string) *SMTP {
+	s := &SMTP{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return s
+}
+
+// Send -.
+func (s *SMTP) Send(_ context.Context, msg Message) error {
+	sender, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("mailer - SMTP - Send - mail.ParseAddress: %w", err)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	err = smtp.SendMail(s.addr, s.auth, sender.Address, []string{msg.To}, []byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("mailer - SMTP - Send - smtp.SendMail: %w", err)
+	}
+
+	return nil
+}
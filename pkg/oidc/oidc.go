@@ -0,0 +1,297 @@
+// Package oidc implements the OpenID Connect authorization code flow with PKCE
+// against any provider that supports discovery.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultTimeout = 10 * time.Second
+	_defaultLeeway  = time.Minute
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNonceMismatch  = errors.New("id token nonce mismatch")
+)
+
+// Claims are the ID token claims used to identify a user.
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	ExpiresAt         int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider -.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	client       *http.Client
+	now          func() time.Time
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// New -. Discovery is deferred to the first request, so an unreachable
+// provider does not keep the application from starting.
+func New(issuer, clientID, clientSecret, redirectURL string, opts ...Option) *Provider {
+	p := &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       []string{"openid", "email", "profile"},
+		client:       &http.Client{Timeout: _defaultTimeout},
+		now:          time.Now,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// AuthCodeURL returns the provider URL the user is sent to. The verifier is
+// only sent as its S256 challenge and has to be passed to Exchange later.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", strings.Join(p.scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", Challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified ID token claims.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oidc - Exchange - http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - Exchange - client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - Exchange - json.Decode: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc - Exchange - token endpoint: %s %s", token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("oidc - Exchange: %w: missing from token response", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verify(ctx, d, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return claims, nil
+}
+
+// verify checks signature, issuer, audience and expiry of an ID token.
+func (p *Provider) verify(ctx context.Context, d *discovery, rawToken string) (*Claims, error) {
+	payload, err := verifySignature(rawToken, func(kid string) (*rsa.PublicKey, error) {
+		return p.getKey(ctx, d, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - verify - json.Unmarshal: %w", ErrInvalidIDToken)
+	}
+
+	if claims.Issuer != d.Issuer {
+		return nil, fmt.Errorf("oidc - verify: %w: unexpected issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+
+	if !slices.Contains(claims.Audience, p.clientID) {
+		return nil, fmt.Errorf("oidc - verify: %w: not issued for this client", ErrInvalidIDToken)
+	}
+
+	if p.now().After(time.Unix(claims.ExpiresAt, 0).Add(_defaultLeeway)) {
+		return nil, fmt.Errorf("oidc - verify: %w: expired", ErrInvalidIDToken)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("oidc - verify: %w: missing subject", ErrInvalidIDToken)
+	}
+
+	return &claims, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discovery
+	err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", &d)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - discovery: %w", err)
+	}
+
+	if strings.TrimSuffix(d.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("oidc - discovery: issuer %q does not match %q", d.Issuer, p.issuer)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc - discovery: incomplete provider metadata")
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// getKey returns the signing key with the given ID, refetching the key set
+// once if the provider has rotated its keys.
+func (p *Provider) getKey(ctx context.Context, d *discovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var set jwks
+	err := p.getJSON(ctx, d.JWKSURI, &set)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - jwks: %w", err)
+	}
+
+	keys, err := set.rsaKeys()
+	if err != nil {
+		return nil, fmt.Errorf("oidc - jwks: %w", err)
+	}
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("oidc - jwks: %w: unknown key %q", ErrInvalidIDToken, kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, endpoint)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// audience accepts both forms of the "aud" claim: a string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*a = list
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"social/api/pkg/oidc"
+	"social/api/pkg/oidc/oidctest"
+)
+
+const (
+	clientID     = "social-api"
+	clientSecret = "s3cret"
+	redirectURL  = "http://localhost:8080/auth/oidc/test/callback"
+)
+
+var alice = oidctest.User{
+	Subject:           "alice-1",
+	Email:             "alice@example.com",
+	EmailVerified:     true,
+	Name:              "Alice",
+	PreferredUsername: "alice",
+}
+
+func newIssuer(t *testing.T) *oidctest.Issuer {
+	t.Helper()
+
+	issuer, err := oidctest.NewIssuer(clientID, clientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(issuer.Close)
+	issuer.SetUser(alice)
+
+	return issuer
+}
+
+// login runs the browser part of the flow and returns the authorization code.
+func login(t *testing.T, issuer *oidctest.Issuer, provider *oidc.Provider, verifier, nonce string) string {
+	t.Helper()
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	code, state, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("state = %q, want %q", state, "state-1")
+	}
+
+	return code
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	issuer := newIssuer(t)
+	provider := oidc.New(issuer.URL, clientID, clientSecret, redirectURL)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := u.Query()
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             clientID,
+		"redirect_uri":          redirectURL,
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        oidc.Challenge("verifier"),
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := q.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newIssuer(t)
+	provider := oidc.New(issuer.URL, clientID, clientSecret, redirectURL)
+
+	code := login(t, issuer, provider, "verifier-1", "nonce-1")
+
+	claims, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Subject != alice.Subject || claims.Email != alice.Email || !claims.EmailVerified {
+		t.Errorf("claims = %+v, want subject %q and verified email %q", claims, alice.Subject, alice.Email)
+	}
+
+	// Authorization codes are single-use
+	_, err = provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err == nil {
+		t.Error("second Exchange with the same code succeeded")
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		modify   func(map[string]any)
+		clock    func() time.Time
+		wantErr  error
+	}{
+		{
+			name:     "wrong PKCE verifier",
+			verifier: "other-verifier",
+			nonce:    "nonce-1",
+		},
+		{
+			name:     "nonce mismatch",
+			verifier: "verifier-1",
+			nonce:    "other-nonce",
+			wantErr:  oidc.ErrNonceMismatch,
+		},
+		{
+			name:     "foreign audience",
+			verifier: "verifier-1",
+			nonce:    "nonce-1",
+			modify:   func(c map[string]any) { c["aud"] = []string{"someone-else"} },
+			wantErr:  oidc.ErrInvalidIDToken,
+		},
+		{
+			name:     "foreign issuer",
+			verifier: "verifier-1",
+			nonce:    "nonce-1",
+			modify:   func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+			wantErr:  oidc.ErrInvalidIDToken,
+		},
+		{
+			name:     "expired",
+			verifier: "verifier-1",
+			nonce:    "nonce-1",
+			clock:    func() time.Time { return time.Now().Add(time.Hour) },
+			wantErr:  oidc.ErrInvalidIDToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newIssuer(t)
+			issuer.ModifyClaims = tt.modify
+
+			var opts []oidc.Option
+			if tt.clock != nil {
+				opts = append(opts, oidc.Clock(tt.clock))
+			}
+			provider := oidc.New(issuer.URL, clientID, clientSecret, redirectURL, opts...)
+
+			code := login(t, issuer, provider, "verifier-1", "nonce-1")
+
+			_, err := provider.Exchange(context.Background(), code, tt.verifier, tt.nonce)
+			if err == nil {
+				t.Fatal("Exchange succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsForeignSignature(t *testing.T) {
+	issuer := newIssuer(t)
+	provider := oidc.New(issuer.URL, clientID, clientSecret, redirectURL)
+
+	// The provider caches the issuer's key from the first exchange
+	code := login(t, issuer, provider, "verifier-1", "nonce-1")
+	if _, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	// Later tokens carry the same key ID but are signed by a different key
+	if err := issuer.ReplaceSigningKey(); err != nil {
+		t.Fatal(err)
+	}
+
+	code = login(t, issuer, provider, "verifier-2", "nonce-2")
+	_, err := provider.Exchange(context.Background(), code, "verifier-2", "nonce-2")
+	if !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Errorf("err = %v, want %v", err, oidc.ErrInvalidIDToken)
+	}
+}
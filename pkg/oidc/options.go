@@ -0,0 +1,30 @@
+package oidc
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option -.
+type Option func(*Provider)
+
+// HTTPClient -.
+func HTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// Scopes -.
+func Scopes(scopes ...string) Option {
+	return func(p *Provider) {
+		p.scopes = scopes
+	}
+}
+
+// Clock -.
+func Clock(now func() time.Time) Option {
+	return func(p *Provider) {
+		p.now = now
+	}
+}
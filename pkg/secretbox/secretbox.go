@@ -0,0 +1,72 @@
+// Package secretbox encrypts small secrets at rest with AES-256-GCM.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const _keySize = 32
+
+var (
+	// ErrInvalidKey is returned when the key is not 32 base64 encoded bytes.
+	ErrInvalidKey = errors.New("secretbox: key must be 32 base64 encoded bytes")
+	// ErrInvalidCiphertext is returned when a value was not sealed with this key.
+	ErrInvalidCiphertext = errors.New("secretbox: invalid ciphertext")
+)
+
+// Box -.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New -.
+func New(key string) (*Box, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != _keySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox - New - aes.NewCipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox - New - cipher.NewGCM: %w", err)
+	}
+
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext and returns the nonce and ciphertext base64 encoded.
+func (b *Box) Seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("secretbox - Seal - rand.Read: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(b.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Open decrypts a value produced by Seal.
+func (b *Box) Open(sealed string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
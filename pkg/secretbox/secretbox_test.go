@@ -0,0 +1,94 @@
+package secretbox_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"social/api/pkg/secretbox"
+)
+
+var key = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{"32 bytes", key, nil},
+		{"16 bytes", base64.StdEncoding.EncodeToString(make([]byte, 16)), secretbox.ErrInvalidKey},
+		{"not base64", "not base64!", secretbox.ErrInvalidKey},
+		{"empty", "", secretbox.ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := secretbox.New(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	box, err := secretbox.New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	sealed, err := box.Seal(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := box.Seal(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == again {
+		t.Error("Seal() returned the same ciphertext twice, want a fresh nonce each time")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw[len(raw)-1] ^= 1
+	flipped := base64.StdEncoding.EncodeToString(raw)
+
+	otherKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	other, err := secretbox.New(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		box     *secretbox.Box
+		sealed  string
+		wantErr error
+	}{
+		{"sealed", box, sealed, nil},
+		{"sealed again", box, again, nil},
+		{"other key", other, sealed, secretbox.ErrInvalidCiphertext},
+		{"flipped bit", box, flipped, secretbox.ErrInvalidCiphertext},
+		{"shorter than nonce", box, base64.StdEncoding.EncodeToString([]byte("short")), secretbox.ErrInvalidCiphertext},
+		{"not base64", box, "not base64!", secretbox.ErrInvalidCiphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.box.Open(tt.sealed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, plaintext) {
+				t.Errorf("Open() = %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"social/api/pkg/totp"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, cut to the last 6 of the 8 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			code, err := totp.Code(rfcSecret, time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.want {
+				t.Errorf("Code() = %q, want %q", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := totp.Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := now.Unix() / 30
+
+	tests := []struct {
+		name     string
+		code     string
+		secret   string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", code, rfcSecret, now, step, true},
+		{"lowercase secret", code, strings.ToLower(rfcSecret), now, step, true},
+		{"clock behind", code, rfcSecret, now.Add(30 * time.Second), step, true},
+		{"clock ahead", code, rfcSecret, now.Add(-30 * time.Second), step, true},
+		{"too late", code, rfcSecret, now.Add(90 * time.Second), 0, false},
+		{"wrong code", "000000", rfcSecret, now, 0, false},
+		{"wrong length", code[:5], rfcSecret, now, 0, false},
+		{"invalid secret", code, "not base32!", now, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := totp.Validate(tt.code, tt.secret, tt.at)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatalf("Code() with a generated secret: %v", err)
+	}
+	if _, ok := totp.Validate(code, secret, time.Now()); !ok {
+		t.Error("Validate() rejected the current code of a generated secret")
+	}
+}